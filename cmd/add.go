@@ -24,6 +24,12 @@ Examples:
   prj add ~/Projects            Add another folder
   prj add .                     Add the current directory`,
 	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		folder := expandPath(args[0])
 
@@ -32,6 +38,12 @@ Examples:
 			return fmt.Errorf("folder does not exist: %s", folder)
 		}
 
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var changesSince string
+
+var changesCmd = &cobra.Command{
+	Use:   "changes",
+	Short: "Show what changed between scans (new repos, status flips, new tech...)",
+	Long: `Print the change feed recorded by "prj scan". Each scan compares the
+new data with what was stored before and logs:
+
+  - new        a repo appeared (or came back)
+  - removed    a repo was deleted or its folder is no longer tracked
+  - moved      a repo was found at a new path
+  - status     e.g. active → paused
+  - tech       new tech detected
+  - contributors  new commit authors
+  - todos      open/closed TODO counts changed
+
+Without --since, only the changes from the most recent scan are shown.
+--since takes a date (2026-01-31) or a relative age (7d, 2w, 3m).
+
+Examples:
+  prj changes                  What the last scan changed
+  prj changes --since 7d       Everything from the past week
+  prj changes --since 2026-01-01`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if changesSince == "" {
+			return printLastScanChanges()
+		}
+		t, err := parseSince(changesSince)
+		if err != nil {
+			return err
+		}
+		changes, err := history.LoadChanges(t.UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("load changes: %w", err)
+		}
+		display.PrintChanges(changes)
+		return nil
+	},
+}
+
+// printLastScanChanges prints the changes recorded by the most recent scan.
+// Stores scanned before the last scan time was recorded fall back to the
+// newest recorded changes.
+func printLastScanChanges() error {
+	last, err := history.LastScan()
+	if err != nil {
+		return fmt.Errorf("load last scan: %w", err)
+	}
+	changes, err := history.LoadChanges(last)
+	if err != nil {
+		return fmt.Errorf("load changes: %w", err)
+	}
+	if last == "" && len(changes) > 0 {
+		last = changes[len(changes)-1].At
+	}
+
+	var latest []history.Change
+	for _, c := range changes {
+		if c.At == last {
+			latest = append(latest, c)
+		}
+	}
+	if len(latest) == 0 {
+		fmt.Println("No changes in the last scan.")
+		return nil
+	}
+	display.PrintChanges(latest)
+	return nil
+}
+
+// parseSince accepts YYYY-MM-DD, RFC 3339, or a relative age like 7d, 2w, 3m.
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if len(s) >= 2 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err == nil && n >= 0 {
+			now := time.Now()
+			switch strings.ToLower(s[len(s)-1:]) {
+			case "d":
+				return now.AddDate(0, 0, -n), nil
+			case "w":
+				return now.AddDate(0, 0, -7*n), nil
+			case "m":
+				return now.AddDate(0, -n, 0), nil
+			case "y":
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (want YYYY-MM-DD or an age like 7d, 2w, 3m)", s)
+}
+
+func init() {
+	changesCmd.Flags().StringVar(&changesSince, "since", "", "Show changes since a date (YYYY-MM-DD) or age (7d, 2w, 3m)")
+	changesCmd.RegisterFlagCompletionFunc("since", noCompletion)
+	rootCmd.AddCommand(changesCmd)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Print a shell completion script",
+	Long: `Print the completion script for your shell. Besides commands and
+flags, it completes project names (prj info, path, cd, note, ...),
+statuses, types, tech, tags, marks, columns and view names, read from
+the store as you type.
+
+Bash (needs the bash-completion package):
+  echo 'source <(prj completion bash)' >> ~/.bashrc
+  # or once, system-wide:
+  prj completion bash > /etc/bash_completion.d/prj
+
+Zsh:
+  echo 'source <(prj completion zsh)' >> ~/.zshrc
+  # or into a directory on $fpath, e.g. with oh-my-zsh:
+  prj completion zsh > ~/.oh-my-zsh/completions/_prj
+  # completion must be enabled: autoload -U compinit; compinit
+
+Fish:
+  prj completion fish > ~/.config/fish/completions/prj.fish
+
+PowerShell:
+  prj completion powershell >> $PROFILE
+
+Start a new shell for the change to take effect.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := os.Stdout
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		return fmt.Errorf("unsupported shell %q (want bash, zsh, fish or powershell)", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// completionProjects loads the stored projects for a completion. Errors
+// just mean nothing to offer.
+func completionProjects() []*project.Project {
+	st, err := openStore()
+	if err != nil {
+		return nil
+	}
+	defer st.Close()
+	projects, _ := st.Load()
+	return projects
+}
+
+// completeProject completes the first argument with project names, each
+// described by its path. Names shared by several projects are offered as
+// folder/name instead, which resolves to just one of them.
+func completeProject(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return projectNames(completionProjects(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectNames offers project names matching toComplete. A name shared by
+// several projects is offered qualified as folder/name, whether the folder
+// or the name was typed; shells that only keep candidates starting with
+// what was typed also get the bare name, which prompts for a pick when run.
+func projectNames(projects []*project.Project, toComplete string) []string {
+	count := map[string]int{}
+	for _, p := range projects {
+		count[p.Name]++
+	}
+	var out []string
+	for _, p := range projects {
+		name := p.Name
+		if count[name] == 1 {
+			if hasPrefixFold(name, toComplete) {
+				out = append(out, name+"\t"+p.Path)
+			}
+			continue
+		}
+		qualified := filepath.Base(filepath.Dir(p.Path)) + "/" + name
+		if hasPrefixFold(qualified, toComplete) || hasPrefixFold(name, toComplete) {
+			out = append(out, qualified+"\t"+p.Path)
+		}
+	}
+	for name, n := range count {
+		if n > 1 && hasPrefixFold(name, toComplete) {
+			out = append(out, fmt.Sprintf("%s\t%d projects", name, n))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// completeWords returns a completion function offering fixed words.
+func completeWords(words ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return matching(words, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeValues returns a completion function offering values computed
+// from the stored projects, such as every tech seen.
+func completeValues(values func([]*project.Project) []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return matching(values(completionProjects()), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeList completes a comma-separated list flag such as --columns:
+// the words already typed are kept and not offered again.
+func completeList(words []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		done, last := "", toComplete
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			done, last = toComplete[:i+1], toComplete[i+1:]
+		}
+		chosen := map[string]bool{}
+		for _, w := range strings.Split(done, ",") {
+			chosen[w] = true
+		}
+		var out []string
+		for _, w := range matching(words, last) {
+			if !chosen[w] {
+				out = append(out, done+w)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completeFolder completes the folders in the config.
+func completeFolder(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil || len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matching(cfg.Folders, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeView completes saved view names, each written with prefix ("@"
+// for prj list).
+func completeView(prefix string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cfg, err := config.Load()
+		if err != nil || len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []string
+		for n, v := range cfg.Views {
+			names = append(names, prefix+n+"\t"+shellJoin(v.Args))
+		}
+		sort.Strings(names)
+		return matching(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// noCompletion stops the shell from offering file names for free-text
+// flags.
+func noCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// knownStatuses are the built-in statuses, the overriding marks, the
+// statuses of rules in the config and any others in the store.
+func knownStatuses(projects []*project.Project) []string {
+	statuses := []string{"active", "recent", "paused", "wip", project.MarkArchived, project.MarkAbandoned, project.MarkIdea}
+	if cfg, err := config.Load(); err == nil {
+		for _, r := range cfg.StatusRules {
+			statuses = append(statuses, r.Status)
+		}
+	}
+	for _, p := range projects {
+		statuses = append(statuses, p.EffectiveStatus())
+	}
+	return unique(statuses)
+}
+
+func knownTypes(projects []*project.Project) []string {
+	var types []string
+	for _, p := range projects {
+		types = append(types, p.InferredType)
+	}
+	return unique(types)
+}
+
+func knownTech(projects []*project.Project) []string {
+	var tech []string
+	for _, p := range projects {
+		tech = append(tech, p.TechStack...)
+	}
+	return unique(tech)
+}
+
+func knownTags(projects []*project.Project) []string {
+	var tags []string
+	for _, p := range projects {
+		tags = append(tags, p.Tags...)
+	}
+	return unique(tags)
+}
+
+// unique sorts values and drops duplicates and empty ones.
+func unique(values []string) []string {
+	seen := map[string]bool{"": true}
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func matching(words []string, prefix string) []string {
+	var out []string
+	for _, w := range words {
+		if hasPrefixFold(w, prefix) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+func TestProjectNames(t *testing.T) {
+	projects := []*project.Project{
+		{Name: "api", Path: "/dev/work/api"},
+		{Name: "api", Path: "/dev/play/api"},
+		{Name: "apigen", Path: "/dev/tools/apigen"},
+		{Name: "Web", Path: "/dev/Web"},
+	}
+	tests := []struct {
+		typed string
+		want  []string
+	}{
+		{"", []string{
+			"Web\t/dev/Web",
+			"api\t2 projects",
+			"apigen\t/dev/tools/apigen",
+			"play/api\t/dev/play/api",
+			"work/api\t/dev/work/api",
+		}},
+		// A shared name is offered bare and qualified by its folder.
+		{"ap", []string{
+			"api\t2 projects",
+			"apigen\t/dev/tools/apigen",
+			"play/api\t/dev/play/api",
+			"work/api\t/dev/work/api",
+		}},
+		{"api", []string{
+			"api\t2 projects",
+			"apigen\t/dev/tools/apigen",
+			"play/api\t/dev/play/api",
+			"work/api\t/dev/work/api",
+		}},
+		// Typing the folder narrows to one of them.
+		{"wo", []string{"work/api\t/dev/work/api"}},
+		{"PLAY/", []string{"play/api\t/dev/play/api"}},
+		{"apig", []string{"apigen\t/dev/tools/apigen"}},
+		{"w", []string{"Web\t/dev/Web", "work/api\t/dev/work/api"}},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		got := projectNames(projects, tt.typed)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("projectNames(%q):\n%q\nwant:\n%q", tt.typed, got, tt.want)
+		}
+	}
+}
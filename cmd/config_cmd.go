@@ -13,8 +13,30 @@ var configCmd = &cobra.Command{
 	Short: "Show current configuration (tracked folders, settings)",
 	Long: `Print the current prj configuration as JSON. This includes:
 
-  - folders:     list of parent directories being scanned
-  - cutoff_days: how many days of inactivity before a project is "paused"
+  - folders:      list of parent directories being scanned
+  - active_days:  committed within this many days is "active" (default 30)
+  - cutoff_days:  how many days of inactivity before a project is "paused"
+                  (default 240); in between is "recent"
+  - stalled_days: no commits for this long shows as stalled in "prj status"
+                  (default 180)
+  - wip_keywords: commit message words that make an active project "wip"
+  - folder_thresholds: per-folder overrides of the four settings above,
+                  keyed by folder path, e.g.
+                  {"~/work": {"active_days": 14, "cutoff_days": 60}}
+  - status_rules: ordered custom statuses, each {"status", "when", "color"},
+                  where "when" is an expression over project fields, e.g.
+                  {"status": "blocked", "when": "todo_open > 0 && days_since_commit > 30"}
+                  The first matching rule replaces the built-in status.
+  - storage:      "json" (default) or "sqlite" — see "prj store"
+  - views:        saved "prj list" flags by name, e.g.
+                  {"daily": {"args": ["--status", "active", "--own"]}}
+                  — see "prj view"; a view can set --columns too
+  - columns:      default columns of the "prj list" table, e.g.
+                  ["name", "status", "tech", "todo", "path"]
+  - history_days: how long "prj history" and "prj trends" keep scans
+                  (default 730); -1 keeps them forever
+
+Threshold changes apply on the next "prj scan".
 
 Config is stored at ~/.prj/config.json.
 
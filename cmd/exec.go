@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/peeomid/prj/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execFilter   projectFilters
+	execJobs     int
+	execFailFast bool
+	execDryRun   bool
+	execGroup    bool
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [filters] -- <command> [args...]",
+	Short: "Run a command in every matching project",
+	Long: `Run a command in the directory of every project matching the filters,
+which are the same as "prj list"'s. Put the command after "--".
+
+A single argument after "--" runs through sh -c, so pipes and && work;
+several arguments run the program directly. The command sees
+PRJ_NAME and PRJ_PATH in its environment, and no stdin. Missing
+projects are skipped.
+
+Projects run in parallel (--jobs). Each output line is prefixed with
+the project name as it arrives; --group prints each project's output
+as one block when it finishes instead. A summary of exit statuses
+follows, and prj exits non-zero if any project failed.
+
+--fail-fast stops at the first failure: nothing new starts and the
+commands still running are killed. Ctrl-C stops the run the same way.
+
+Examples:
+  prj exec --status active --tech go -- go test ./...
+  prj exec --own -- git pull --ff-only
+  prj exec --tag acme --group -- 'npm outdated || true'
+  prj exec --where 'dirty' --dry-run -- git status -s
+  prj exec -j 1 --fail-fast -- make build`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		dash := cmd.ArgsLenAtDash()
+		if dash < 0 || dash == len(args) {
+			return fmt.Errorf(`no command given; put it after "--", e.g. prj exec --tech go -- go test ./...`)
+		}
+		if dash > 0 {
+			return fmt.Errorf("unexpected argument %q before \"--\" (filters are flags, e.g. --tech go)", args[0])
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if execJobs < 1 {
+			return fmt.Errorf("--jobs must be at least 1")
+		}
+		q, err := execFilter.query()
+		if err != nil {
+			return err
+		}
+
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		projects, err := st.Query(q)
+		st.Close()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+		project.SortBy(projects, "name")
+
+		var targets []runner.Target
+		missing := 0
+		for _, p := range projects {
+			if p.Missing != "" {
+				missing++
+				continue
+			}
+			targets = append(targets, runner.Target{
+				Name: p.Name,
+				Dir:  p.Path,
+				Env:  []string{"PRJ_NAME=" + p.Name, "PRJ_PATH=" + p.Path},
+			})
+		}
+		if missing > 0 {
+			fmt.Fprintf(os.Stderr, "%s %d missing project(s)\n", display.Gray("skipping"), missing)
+		}
+		if len(targets) == 0 {
+			fmt.Println("No matching projects.")
+			return nil
+		}
+
+		argv := args
+		if len(argv) == 1 {
+			argv = []string{"sh", "-c", argv[0]}
+		}
+
+		if execDryRun {
+			fmt.Printf("Would run %s in %d projects:\n", display.Bold(shellJoin(args)), len(targets))
+			for _, t := range targets {
+				fmt.Printf("  %-20s %s\n", t.Name, display.Gray(t.Dir))
+			}
+			return nil
+		}
+
+		// From here on a failure is the command's, not a usage mistake.
+		cmd.SilenceUsage = true
+
+		// The commands run in their own process groups, so a Ctrl-C doesn't
+		// reach them; kill them ourselves.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		out := newExecOutput(targets, execGroup)
+		start := time.Now()
+		results := runner.Run(ctx, argv, targets, runner.Options{
+			Jobs:     execJobs,
+			FailFast: execFailFast,
+			Output:   out.writers,
+			Done:     out.done,
+		})
+		failed := printExecSummary(results, time.Since(start))
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d projects failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	execFilter.addFlags(execCmd)
+	execCmd.Flags().IntVarP(&execJobs, "jobs", "j", runtime.NumCPU(), "Number of projects to run at once")
+	execCmd.Flags().BoolVar(&execFailFast, "fail-fast", false, "Stop at the first failure, killing running commands")
+	execCmd.Flags().BoolVar(&execDryRun, "dry-run", false, "List the projects the command would run in")
+	execCmd.Flags().BoolVar(&execGroup, "group", false, "Print each project's output as one block when it finishes")
+	rootCmd.AddCommand(execCmd)
+}
+
+// execOutput routes each project's output to the terminal: line by line
+// with a name prefix, or buffered per project with --group.
+type execOutput struct {
+	mu      sync.Mutex // serializes writes to stdout and stderr
+	group   bool
+	width   int
+	colors  map[string]func(...interface{}) string
+	pending map[string][]*prefixWriter
+	buffers map[string]*bytes.Buffer
+	printed bool // a --group block has been printed
+}
+
+var prefixColors = []func(...interface{}) string{display.Cyan, display.Yellow, display.Green, display.Blue}
+
+func newExecOutput(targets []runner.Target, group bool) *execOutput {
+	o := &execOutput{
+		group:   group,
+		colors:  map[string]func(...interface{}) string{},
+		pending: map[string][]*prefixWriter{},
+		buffers: map[string]*bytes.Buffer{},
+	}
+	for i, t := range targets {
+		o.width = max(o.width, len(t.Name))
+		o.colors[t.Dir] = prefixColors[i%len(prefixColors)]
+	}
+	return o
+}
+
+func (o *execOutput) writers(t runner.Target) (io.Writer, io.Writer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.group {
+		// One buffer for both streams keeps them in order.
+		b := &bytes.Buffer{}
+		o.buffers[t.Dir] = b
+		w := &lockedWriter{mu: &o.mu, w: b}
+		return w, w
+	}
+	prefix := o.colors[t.Dir](fmt.Sprintf("%-*s", o.width, t.Name)) + display.Gray(" │ ")
+	stdout := &prefixWriter{mu: &o.mu, out: os.Stdout, prefix: prefix}
+	stderr := &prefixWriter{mu: &o.mu, out: os.Stderr, prefix: prefix}
+	o.pending[t.Dir] = []*prefixWriter{stdout, stderr}
+	return stdout, stderr
+}
+
+// done prints a finished project's output block, or the unterminated last
+// line of its prefixed output.
+func (o *execOutput) done(r runner.Result) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.group {
+		for _, w := range o.pending[r.Target.Dir] {
+			w.flush()
+		}
+		return
+	}
+	status := display.Green("ok")
+	if !r.OK() {
+		status = display.Red(execStatus(r))
+	}
+	if o.printed {
+		fmt.Println()
+	}
+	o.printed = true
+	fmt.Printf("%s %s %s\n", display.Bold("── "+r.Target.Name), display.Gray(r.Target.Dir), status)
+	b := o.buffers[r.Target.Dir]
+	os.Stdout.Write(b.Bytes())
+	if b.Len() > 0 && !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+		fmt.Println()
+	}
+}
+
+// prefixWriter writes whole lines to out, each starting with prefix.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes a last line that didn't end in a newline. The caller holds
+// mu.
+func (w *prefixWriter) flush() {
+	if len(w.buf) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+		w.buf = nil
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// printExecSummary prints one line per project and the totals, and returns
+// how many failed. Projects killed or skipped because the run stopped
+// (--fail-fast or an interrupt) aren't counted as failures of their own.
+func printExecSummary(results []runner.Result, took time.Duration) int {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Target.Name))
+	}
+	ok, failed, killed, skipped := 0, 0, 0, 0
+	fmt.Println()
+	for _, r := range results {
+		name := fmt.Sprintf("%-*s", width, r.Target.Name)
+		switch {
+		case r.Skipped:
+			skipped++
+			fmt.Printf("  %s %s  %s\n", display.Gray("-"), name, display.Gray("skipped"))
+		case r.Canceled:
+			killed++
+			fmt.Printf("  %s %s  %s  %s\n", display.Yellow("✗"), name, display.Yellow(execStatus(r)), display.Gray(roundDuration(r.Duration)))
+		case r.OK():
+			ok++
+			fmt.Printf("  %s %s  %s\n", display.Green("✓"), name, display.Gray(roundDuration(r.Duration)))
+		default:
+			failed++
+			fmt.Printf("  %s %s  %s  %s\n", display.Red("✗"), name, display.Red(execStatus(r)), display.Gray(roundDuration(r.Duration)))
+		}
+	}
+
+	parts := []string{display.Green(fmt.Sprintf("%d ok", ok))}
+	if failed > 0 {
+		parts = append(parts, display.Red(fmt.Sprintf("%d failed", failed)))
+	}
+	if killed > 0 {
+		parts = append(parts, display.Yellow(fmt.Sprintf("%d killed", killed)))
+	}
+	if skipped > 0 {
+		parts = append(parts, display.Gray(fmt.Sprintf("%d skipped", skipped)))
+	}
+	fmt.Printf("\n%s in %s\n", strings.Join(parts, ", "), roundDuration(took))
+	return failed
+}
+
+// execStatus says how a failed project failed.
+func execStatus(r runner.Result) string {
+	switch {
+	case r.Canceled:
+		return "killed"
+	case r.ExitCode >= 0:
+		return fmt.Sprintf("exit %d", r.ExitCode)
+	}
+	return r.Err.Error()
+}
+
+func roundDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"github.com/peeomid/prj/internal/project"
+	"github.com/peeomid/prj/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// projectFilters are the project filter flags "prj list" and "prj exec"
+// share.
+type projectFilters struct {
+	status string
+	mark   string
+	typ    string
+	tech   string
+	tag    string
+	own    bool
+	forks  bool
+	search string
+	where  string
+}
+
+// addFlags registers the filter flags, and their completions, on cmd.
+func (f *projectFilters) addFlags(cmd *cobra.Command) {
+	fs := cmd.Flags()
+	fs.StringVar(&f.status, "status", "", "Filter by status (active/recent/paused/wip, or archived/abandoned/idea)")
+	fs.StringVar(&f.mark, "mark", "", "Filter by mark ("+project.MarksHelp()+")")
+	fs.StringVar(&f.typ, "type", "", "Filter by inferred type")
+	fs.StringVar(&f.tech, "tech", "", "Filter by tech stack")
+	fs.StringVar(&f.tag, "tag", "", "Filter by tag (see prj tag)")
+	fs.BoolVar(&f.own, "own", false, "Show only own projects (not forks)")
+	fs.BoolVar(&f.forks, "forks", false, "Show only forks")
+	fs.StringVar(&f.search, "search", "", "Search name/path/tags/note")
+	fs.StringVar(&f.where, "where", "", "Filter with an expression, e.g. 'status in (active, wip) and commits > 20'")
+
+	cmd.RegisterFlagCompletionFunc("status", completeValues(knownStatuses))
+	cmd.RegisterFlagCompletionFunc("mark", completeWords(project.Marks...))
+	cmd.RegisterFlagCompletionFunc("type", completeValues(knownTypes))
+	cmd.RegisterFlagCompletionFunc("tech", completeValues(knownTech))
+	cmd.RegisterFlagCompletionFunc("tag", completeValues(knownTags))
+	cmd.RegisterFlagCompletionFunc("search", noCompletion)
+	cmd.RegisterFlagCompletionFunc("where", noCompletion)
+}
+
+// query turns the flags into a store query.
+func (f *projectFilters) query() (store.Query, error) {
+	q := store.Query{
+		Status: f.status,
+		Mark:   f.mark,
+		Type:   f.typ,
+		Tech:   f.tech,
+		Tag:    f.tag,
+		Own:    f.own,
+		Forks:  f.forks,
+		Search: f.search,
+	}
+	if f.where != "" {
+		where, err := compileWhere(f.where)
+		if err != nil {
+			return q, err
+		}
+		q.Where = where
+	}
+	return q, nil
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var hereAdd bool
+
+var hereCmd = &cobra.Command{
+	Use:   "here",
+	Short: "Show the project you're in",
+	Long: `Show the detail view of the project containing the current directory,
+like "prj info" does for a name. "prj info" with no argument does the
+same.
+
+The directory and its parents are matched against the stored
+projects, so this works from anywhere inside a repo and finds the
+innermost stored repo when they nest.
+
+A repo that isn't stored yet is scanned on the spot. Its parent folder
+can then be added to the scan list, and the repo saved: on a terminal
+you're asked, elsewhere pass --add.
+
+Examples:
+  prj here              Details of the repo you're in
+  prj here -o json      The same as JSON
+  prj here --add        Scan an unknown repo and track its parent folder`,
+	Args:        cobra.NoArgs,
+	Annotations: outputCommands,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHere()
+	},
+}
+
+func init() {
+	hereCmd.Flags().BoolVar(&hereAdd, "add", false, "If the repo isn't stored, track its parent folder and save it")
+	rootCmd.AddCommand(hereCmd)
+}
+
+// runHere prints the project containing the working directory.
+func runHere() error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	st, err := openStore()
+	if err != nil {
+		return err
+	}
+	projects, err := st.Load()
+	st.Close()
+	if err != nil {
+		return fmt.Errorf("load projects: %w", err)
+	}
+	if p := projectAt(projects, cwd); p != nil {
+		return printProject(p)
+	}
+
+	root := gitRoot(cwd)
+	if root == "" {
+		return fmt.Errorf("not inside a git repository: %s", cwd)
+	}
+	return showUnstored(root)
+}
+
+// projectAt returns the innermost stored project containing dir, trying
+// the path as given and with symlinks resolved.
+func projectAt(projects []*project.Project, dir string) *project.Project {
+	byPath := make(map[string]*project.Project, len(projects))
+	for _, p := range projects {
+		byPath[filepath.Clean(p.Path)] = p
+	}
+	dirs := []string{dir}
+	if real, err := filepath.EvalSymlinks(dir); err == nil && real != dir {
+		dirs = append(dirs, real)
+	}
+	for _, d := range dirs {
+		for {
+			if p := byPath[d]; p != nil {
+				return p
+			}
+			parent := filepath.Dir(d)
+			if parent == d {
+				break
+			}
+			d = parent
+		}
+	}
+	return nil
+}
+
+// gitRoot returns the closest directory at or above dir holding a .git
+// (a directory, or a file for worktrees and submodules), or "".
+func gitRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// showUnstored scans a repo that isn't in the store, prints it, and offers
+// to track its parent folder.
+func showUnstored(root string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	classifier, err := project.NewClassifier(cfg)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	p := project.Extract(root, nil, classifier)
+	if err := printProject(p); err != nil {
+		return err
+	}
+
+	parent := filepath.Dir(root)
+	tracked := trackedFolder(cfg, root)
+	add := hereAdd
+	if !add && canPrompt() && outputFormat == display.FormatTable && outputTemplate == "" {
+		question := fmt.Sprintf("%s isn't stored yet. Track %s and save it?", p.Name, parent)
+		if tracked != "" {
+			question = fmt.Sprintf("%s isn't stored yet (%s is tracked but not rescanned). Save it?", p.Name, tracked)
+		}
+		add = confirm(question)
+	} else if !add {
+		fmt.Fprintf(os.Stderr, "\n%s isn't stored yet. Run: prj here --add\n", p.Name)
+	}
+	if !add {
+		return nil
+	}
+
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if tracked == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if cfg.AddFolder(parent) {
+			if err := config.Save(cfg); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Added: %s\n", parent)
+		}
+	}
+
+	st, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if err := st.Upsert(p); err != nil {
+		return fmt.Errorf("save store: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Saved %s\n", p.Name)
+	return nil
+}
+
+// trackedFolder returns the configured folder that contains path, or "".
+func trackedFolder(cfg *config.Config, path string) string {
+	for _, f := range cfg.Folders {
+		if path == f || strings.HasPrefix(path, f+string(os.PathSeparator)) {
+			return f
+		}
+	}
+	return ""
+}
+
+// confirm asks a yes/no question on stderr; anything but y or yes is no.
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "\n%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
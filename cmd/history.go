@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "Show how one project's status, commits, TODOs and tech changed over time",
+	Long: `Every "prj scan" that changes something records a compact snapshot of
+each project: status, commit count (8 months), open/closed TODOs and
+tech stack. This shows a project's snapshots over time, one row per
+change.
+
+History follows a project across moves and renames (matched by its
+stable ID). Snapshots are stored in ~/.prj/history.jsonl and kept for
+history_days (default 730) — see "prj config".
+
+Examples:
+  prj history myapp        Status and commit trend for myapp`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		projects, err := st.Load()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+
+		p, err := resolveProject(projects, args[0])
+		if err != nil {
+			return err
+		}
+
+		scans, err := history.Load()
+		if err != nil {
+			return fmt.Errorf("load history: %w", err)
+		}
+
+		display.PrintHistory(p, history.ForProject(scans, p))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
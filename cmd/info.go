@@ -1,53 +1,119 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/peeomid/prj/internal/display"
-	"github.com/peeomid/prj/internal/store"
+	"github.com/peeomid/prj/internal/frecency"
+	"github.com/peeomid/prj/internal/project"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var infoCmd = &cobra.Command{
-	Use:   "info <name>",
+	Use:   "info [name]",
 	Short: "Show full details for a single project",
 	Long: `Display everything known about a project: description, tech stack,
 git history, recent commits, contributors, deployment config,
 reference files, TODO counts, and more.
 
-Supports partial name matching — you don't need the exact name.
+You don't need the exact name. Projects are ranked by how well they
+match: full path, then folder/name, exact name, name prefix, name
+substring, remote repo name, a path segment, path or remote substring,
+and finally the letters of the query in order ("mapi" finds "my-api").
+Between equally good kinds of match, the project you "prj cd" into
+most wins. If the best match is still shared, you pick one (on a
+terminal) or get the list of candidates; qualify the name with its
+folder to choose.
+
+Without a name, shows the project you're in (see "prj here").
 
 Examples:
   prj info myapp           Exact match on project name "myapp"
   prj info api             Partial match — finds "my-api-server" etc.
-  prj info openclaw        Full detail view for openclaw`,
-	Args: cobra.ExactArgs(1),
+  prj info work/api        The "api" under a folder named work
+  prj info ~/work/api      By path
+  prj info                 The project containing the current directory
+  prj info openclaw        Full detail view for openclaw
+  prj info myapp -o json   The stored record as JSON (or yaml, csv, ...)`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProject,
+	Annotations:       outputCommands,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := strings.ToLower(args[0])
+		if len(args) == 0 {
+			return runHere()
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
 
-		projects, err := store.Load()
+		projects, err := st.Load()
 		if err != nil {
 			return fmt.Errorf("load projects: %w", err)
 		}
 
-		for _, p := range projects {
-			if strings.ToLower(p.Name) == name {
-				display.PrintDetail(p)
-				return nil
-			}
+		p, err := resolveProject(projects, args[0])
+		if err != nil {
+			return err
 		}
+		return printProject(p)
+	},
+}
 
-		// Partial match
-		for _, p := range projects {
-			if strings.Contains(strings.ToLower(p.Name), name) {
-				display.PrintDetail(p)
-				return nil
-			}
-		}
+// resolveProject finds the project query refers to, ranked by
+// project.Rank. Among equally good kinds of match, the project jumped into
+// most (see "prj cd") wins. When several still match equally well, the
+// user picks one if we can prompt; otherwise the error lists them.
+func resolveProject(projects []*project.Project, query string) (*project.Project, error) {
+	// Frecency is only a hint; an unreadable file just means no history.
+	db, _ := frecency.Load()
+	now := time.Now()
+	p, err := project.ResolveBy(projects, query, func(p *project.Project) float64 {
+		return db.Score(p.Path, now)
+	})
+	var amb *project.AmbiguousError
+	if errors.As(err, &amb) && canPrompt() {
+		return pickProject(amb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("project not found: %s", query)
+	}
+	return p, nil
+}
 
-		return fmt.Errorf("project not found: %s", args[0])
-	},
+// canPrompt reports whether we can ask the user something: stdin and
+// stderr, where prompts go, are both a terminal. Stdout may be captured, as
+// in cd "$(prj path api)".
+func canPrompt() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// pickProject asks the user to choose between tied candidates. The prompt
+// goes to stderr so it never mixes with the command's output.
+func pickProject(amb *project.AmbiguousError) (*project.Project, error) {
+	fmt.Fprintf(os.Stderr, "%q matches %d projects:\n", amb.Query, len(amb.Candidates))
+	for i, c := range amb.Candidates {
+		fmt.Fprintf(os.Stderr, "  %s %-20s %s\n", display.Bold(fmt.Sprintf("%d)", i+1)), c.Project.Name, display.Gray(c.Project.Path))
+	}
+	fmt.Fprintf(os.Stderr, "Pick one [1-%d]: ", len(amb.Candidates))
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(amb.Candidates) {
+		return nil, fmt.Errorf("no project picked")
+	}
+	return amb.Candidates[n-1].Project, nil
 }
 
 func init() {
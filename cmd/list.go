@@ -1,38 +1,163 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/peeomid/prj/internal/config"
 	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/expr"
 	"github.com/peeomid/prj/internal/project"
 	"github.com/peeomid/prj/internal/store"
 	"github.com/spf13/cobra"
 )
 
 var (
-	listStatus string
-	listType   string
-	listTech   string
-	listOwn    bool
-	listForks  bool
-	listSearch string
+	listFilter projectFilters
+	listCols   []string
+	listGroup  string
+	listSums   []string
 	listSort   string
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "Show all scanned projects in a table (with filters and sorting)",
-	Long: `Display a table of all scanned projects. You can filter by status,
+	Use:               "list [@view]",
+	Short:             "Show all scanned projects in a table (with filters and sorting)",
+	Long:              listLongHelp(config.DefaultConfig().Thresholds()),
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeView("@"),
+	Annotations:       outputCommands,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			if err := applyView(cmd, args[0]); err != nil {
+				return err
+			}
+		}
+		return runList(currentListOptions())
+	},
+}
+
+// listOptions is what a "prj list" run shows: the flags, after any view
+// has been applied.
+type listOptions struct {
+	filter projectFilters
+	cols   []string
+	group  string
+	sums   []string
+	sort   string
+}
+
+// currentListOptions snapshots the list flags.
+func currentListOptions() listOptions {
+	return listOptions{
+		filter: listFilter,
+		cols:   listCols,
+		group:  listGroup,
+		sums:   listSums,
+		sort:   listSort,
+	}
+}
+
+// runList prints the projects selected by opts, shared by "prj list" and
+// "prj view".
+func runList(opts listOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	names := opts.cols
+	if len(names) == 0 && outputFormat == display.FormatTable {
+		names = cfg.Columns
+	}
+	var cols []display.Column
+	if len(names) > 0 {
+		if cols, err = display.Columns(names); err != nil {
+			return err
+		}
+	}
+	sums, err := display.SumFields(opts.sums)
+	if err != nil {
+		return err
+	}
+	if len(sums) > 0 && opts.group == "" {
+		return fmt.Errorf("--sum needs --group-by")
+	}
+
+	st, err := store.Open(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	defer st.Close()
+
+	q, err := opts.filter.query()
+	if err != nil {
+		return err
+	}
+	filtered, err := st.Query(q)
+	if err != nil {
+		return fmt.Errorf("load projects: %w", err)
+	}
+
+	// Sort
+	project.SortBy(filtered, opts.sort)
+
+	if opts.group != "" {
+		groups, err := display.GroupProjects(filtered, opts.group, sums)
+		if err != nil {
+			return err
+		}
+		return printGroups(groups, cols, sums)
+	}
+	return printProjects(filtered, cols)
+}
+
+// compileWhere compiles a --where expression, pointing at the offending
+// column when it doesn't parse.
+func compileWhere(src string) (*expr.Expr, error) {
+	where, err := expr.Compile(src, project.Fields)
+	var perr *expr.Error
+	if errors.As(err, &perr) {
+		return nil, fmt.Errorf("invalid --where: %s", perr.Show(src))
+	}
+	return where, err
+}
+
+// listLongHelp builds the list help text so the status ladder shows the
+// thresholds actually configured.
+func listLongHelp(th config.Thresholds) string {
+	return fmt.Sprintf(`Display a table of all scanned projects. You can filter by status,
 type, tech stack, ownership, or a search query. You can also sort
 by name, last commit date, or commit count.
 
-Statuses:
-  active   — committed within the last 30 days
-  wip      — active + recent commit messages contain "WIP"
-  recent   — committed within the last 90 days
-  paused   — no commits in 90+ days
+Statuses (thresholds from "prj config"):
+  active   — committed within the last %[1]d days
+  wip      — active + recent commit messages contain %[3]s
+  recent   — committed within the last %[2]d days
+  paused   — no commits in %[2]d+ days
+
+Projects marked archived, abandoned or idea with "prj mark" show that
+as their status instead; focus (★) and maintained (⚙) are shown next
+to the status and filtered with --mark.
+
+--columns picks the table (and csv/tsv/markdown) columns: any --where
+field below, or todo (open TODOs). Wide text columns are shortened with
+"…" to fit the terminal. Set defaults with "columns" in the config, or
+save --columns in a view.
+
+--group-by splits the list into headed groups with counts: type, status,
+folder (parent directory), tech (the primary, first-detected one),
+remote-host (github.com, gitlab.com, ...) or tag (a project appears under
+each of its tags). Add --sum commits,todo for per-group totals of any
+numeric field.
+
+--where takes an expression for anything the flags can't say. Combine
+comparisons with and/or/not and parentheses; operators are == != < <=
+> >= ~ (regex) !~ and "in (a, b)". List fields (tech, deployment,
+contributors, tags) match if any element does, or every element with
+"all". Dates take 2026-01-31 or an age like 30d, 2w, 6m. Fields:
+  %[4]s
 
 Examples:
   prj list                          Show all projects (sorted by date)
@@ -41,91 +166,77 @@ Examples:
   prj list --type go-app            Only Go projects
   prj list --own                    Only your own projects (not forks)
   prj list --forks                  Only forked projects
-  prj list --search api             Search by name or path
+  prj list --tag acme               Only projects tagged "acme"
+  prj list --search api             Search by name, path, tag or note
   prj list --sort commits           Sort by commit count (most active first)
   prj list --sort name              Sort alphabetically
-  prj list --status active --own    Combine multiple filters`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		projects, err := store.Load()
-		if err != nil {
-			return fmt.Errorf("load projects: %w", err)
-		}
-
-		// Apply filters
-		filtered := filterProjects(projects)
-
-		// Sort
-		sortProjects(filtered, listSort)
-
-		display.PrintTable(filtered)
-		return nil
-	},
+  prj list --mark focus             Only your focus projects
+  prj list --status archived        Projects you archived
+  prj list --status active --own    Combine multiple filters
+  prj list --where 'status in (active, wip) and tech ~ react and commits > 20 and not fork'
+  prj list --where 'last_commit < 1y and deployment ~ vercel'
+  prj list --where 'contributors ~ alice or tags in (acme, globex)'
+  prj list --group-by folder        Projects under each parent folder
+  prj list --group-by tech --sum commits,todo   Per-tech commit and TODO totals
+  prj list @daily                   Run the saved view "daily" (see prj view)
+  prj list -o json                  Full records as JSON (also jsonl, yaml)
+  prj list -o csv --own             Spreadsheet-friendly (also tsv, markdown)
+  prj list --columns name,status,remote,deployment,todo,contributors,path
+  prj list --template '{{.Name}} {{.Path}}'    One line per project`,
+		th.ActiveDays, th.CutoffDays, quoteList(th.WIPKeywords), fieldsHelp())
 }
 
-func filterProjects(projects []*project.Project) []*project.Project {
-	var result []*project.Project
-	for _, p := range projects {
-		if listStatus != "" && p.Status != listStatus {
-			continue
-		}
-		if listType != "" && !strings.Contains(strings.ToLower(p.InferredType), strings.ToLower(listType)) {
-			continue
-		}
-		if listTech != "" && !containsTech(p.TechStack, listTech) {
-			continue
-		}
-		if listOwn && p.IsFork {
-			continue
-		}
-		if listForks && !p.IsFork {
-			continue
-		}
-		if listSearch != "" {
-			q := strings.ToLower(listSearch)
-			if !strings.Contains(strings.ToLower(p.Name), q) &&
-				!strings.Contains(strings.ToLower(p.Path), q) {
-				continue
-			}
-		}
-		result = append(result, p)
+// quoteList renders ["wip", "work in progress"] as "wip" or "work in progress".
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = fmt.Sprintf("%q", s)
 	}
-	return result
+	return strings.Join(quoted, " or ")
 }
 
-func containsTech(stack []string, tech string) bool {
-	t := strings.ToLower(tech)
-	for _, s := range stack {
-		if strings.Contains(strings.ToLower(s), t) {
-			return true
-		}
+// fieldsHelp lists the --where fields with their kinds, wrapped for help.
+func fieldsHelp() string {
+	names := make([]string, 0, len(project.Fields))
+	for n := range project.Fields {
+		names = append(names, n)
 	}
-	return false
-}
+	sort.Strings(names)
 
-func sortProjects(projects []*project.Project, by string) {
-	switch by {
-	case "name":
-		sort.Slice(projects, func(i, j int) bool {
-			return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
-		})
-	case "commits":
-		sort.Slice(projects, func(i, j int) bool {
-			return projects[i].CommitCount8M > projects[j].CommitCount8M
-		})
-	default: // "date" or empty — sort by last commit desc
-		sort.Slice(projects, func(i, j int) bool {
-			return projects[i].LastCommitDate > projects[j].LastCommitDate
-		})
+	var lines []string
+	line := ""
+	for _, n := range names {
+		item := n + " (" + project.Fields[n].String() + ")"
+		if line != "" && len(line)+len(item)+2 > 68 {
+			lines = append(lines, line+",")
+			line = ""
+		}
+		if line != "" {
+			line += ", "
+		}
+		line += item
 	}
+	lines = append(lines, line)
+	return strings.Join(lines, "\n  ")
 }
 
 func init() {
-	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (active/recent/paused/wip)")
-	listCmd.Flags().StringVar(&listType, "type", "", "Filter by inferred type")
-	listCmd.Flags().StringVar(&listTech, "tech", "", "Filter by tech stack")
-	listCmd.Flags().BoolVar(&listOwn, "own", false, "Show only own projects (not forks)")
-	listCmd.Flags().BoolVar(&listForks, "forks", false, "Show only forks")
-	listCmd.Flags().StringVar(&listSearch, "search", "", "Search name/path")
+	defaultHelp := listCmd.HelpFunc()
+	listCmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		if cfg, err := config.Load(); err == nil {
+			c.Long = listLongHelp(cfg.Thresholds())
+		}
+		defaultHelp(c, args)
+	})
+
+	listFilter.addFlags(listCmd)
+	listCmd.Flags().StringSliceVar(&listCols, "columns", nil, "Comma-separated columns, e.g. name,status,tech,todo,path")
+	listCmd.Flags().StringVar(&listGroup, "group-by", "", "Group by: "+strings.Join(display.GroupKeys, ", "))
+	listCmd.Flags().StringSliceVar(&listSums, "sum", nil, "With --group-by, total these numeric fields per group, e.g. commits,todo")
 	listCmd.Flags().StringVar(&listSort, "sort", "date", "Sort by: name, date, commits")
+	listCmd.RegisterFlagCompletionFunc("columns", completeList(display.ColumnNames()))
+	listCmd.RegisterFlagCompletionFunc("group-by", completeWords(display.GroupKeys...))
+	listCmd.RegisterFlagCompletionFunc("sum", completeList(append([]string{"todo"}, display.NumericFields()...)))
+	listCmd.RegisterFlagCompletionFunc("sort", completeWords(project.SortKeys...))
 	rootCmd.AddCommand(listCmd)
 }
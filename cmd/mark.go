@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var markClear bool
+
+var markCmd = &cobra.Command{
+	Use:   "mark <name> [" + project.MarksHelp() + "]",
+	Short: "Set a lifecycle mark on a project (archived, focus, ...) that survives rescans",
+	Long: `Tell prj what a project is, regardless of its commit activity.
+Marks are kept across scans.
+
+  focus       your current focus — highlighted in list and status
+  maintained  kept alive on purpose; not reported as stalled
+  idea        not really started; shown as status "idea"
+  archived    done or shelved; shown as status "archived", never stalled
+  abandoned   given up; shown as status "abandoned", never stalled
+
+Archived, abandoned and idea replace the inferred status (filter them
+with "prj list --status archived"); focus and maintained add a marker
+next to it (filter with "prj list --mark focus").
+
+Examples:
+  prj mark oldapp archived     Stop reporting oldapp as stalled
+  prj mark myapp focus         Pin myapp as a focus project
+  prj mark myapp --clear       Remove the mark`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if markClear {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 1 && !markClear {
+			return matching(project.Marks, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeProject(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mark := ""
+		if !markClear {
+			mark = args[1]
+			if !project.ValidMark(mark) {
+				return fmt.Errorf("unknown mark %q (want %s)", mark, project.MarksHelp())
+			}
+		}
+
+		p, err := updateProject(args[0], func(p *project.Project) error {
+			p.Mark = mark
+			p.MarkedAt = ""
+			if mark != "" {
+				p.MarkedAt = time.Now().UTC().Format(time.RFC3339)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if mark == "" {
+			fmt.Printf("Cleared mark on %s (%s)\n", p.Name, display.StatusLabel(p))
+			return nil
+		}
+		fmt.Printf("Marked %s as %s\n", p.Name, display.StatusLabel(p))
+		return nil
+	},
+}
+
+func init() {
+	markCmd.Flags().BoolVar(&markClear, "clear", false, "Remove the project's mark")
+	rootCmd.AddCommand(markCmd)
+}
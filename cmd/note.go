@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var noteClear bool
+
+var noteCmd = &cobra.Command{
+	Use:   "note <name> [text...]",
+	Short: "Write a free-form note on a project (opens $EDITOR without text)",
+	Long: `Attach a note to a project — what it's for, what's left, who to ask.
+Notes are kept across scans, shown by "prj info" and matched by
+"prj list --search".
+
+Without text, the current note opens in $VISUAL or $EDITOR (vi if
+neither is set); save and quit to store it.
+
+Examples:
+  prj note myapp                          Edit the note in your editor
+  prj note myapp "waiting on client API"  Set the note directly
+  prj note myapp --clear                  Delete the note`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var text string
+		switch {
+		case noteClear:
+		case len(args) > 1:
+			text = strings.Join(args[1:], " ")
+		default:
+			// Edit outside the lock: an editor session can outlast any
+			// scan waiting for it.
+			st, err := openStore()
+			if err != nil {
+				return err
+			}
+			projects, err := st.Load()
+			st.Close()
+			if err != nil {
+				return fmt.Errorf("load projects: %w", err)
+			}
+			p, err := resolveProject(projects, args[0])
+			if err != nil {
+				return err
+			}
+			edited, err := editText(p.Note, "prj-note-"+p.Name)
+			if err != nil {
+				return err
+			}
+			if edited == p.Note {
+				fmt.Printf("Note on %s unchanged\n", p.Name)
+				return nil
+			}
+			text = edited
+			args[0] = p.Path
+		}
+
+		p, err := updateProject(args[0], func(p *project.Project) error {
+			p.Note = strings.TrimSpace(text)
+			p.NoteUpdatedAt = ""
+			if p.Note != "" {
+				p.NoteUpdatedAt = time.Now().UTC().Format(time.RFC3339)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if p.Note == "" {
+			fmt.Printf("Cleared note on %s\n", p.Name)
+			return nil
+		}
+		fmt.Printf("Saved note on %s\n", p.Name)
+		return nil
+	},
+}
+
+// editText opens text in the user's editor and returns what they saved,
+// trimmed.
+func editText(text, name string) (string, error) {
+	f, err := os.CreateTemp("", name+"-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	c := editorCommand(f.Name())
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("run editor: %w", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// editorCommand opens file (or a directory) in $VISUAL, $EDITOR or vi.
+func editorCommand(file string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// Run through the shell so EDITOR="code --wait" works.
+	return exec.Command("sh", "-c", editor+` "$1"`, "sh", file)
+}
+
+func init() {
+	noteCmd.Flags().BoolVar(&noteClear, "clear", false, "Delete the project's note")
+	rootCmd.AddCommand(noteCmd)
+}
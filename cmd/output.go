@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputFormat   string
+	outputTemplate string
+)
+
+// outputAnnotation marks the commands that honor --output and --template.
+const outputAnnotation = "prj:output"
+
+var outputCommands = map[string]string{outputAnnotation: "true"}
+
+// checkOutputFlags validates the global output flags before any command
+// runs, and turns colors off for anything but the table format.
+func checkOutputFlags(cmd *cobra.Command, args []string) error {
+	if err := display.CheckFormat(outputFormat); err != nil {
+		return err
+	}
+	changed := cmd.Flags().Changed("output") || cmd.Flags().Changed("template")
+	if changed && cmd.Annotations[outputAnnotation] == "" {
+		return fmt.Errorf("%s doesn't support --output or --template (list, info, here, status and view do)", cmd.CommandPath())
+	}
+	if outputTemplate != "" && outputFormat != display.FormatTable {
+		return fmt.Errorf("use either --output or --template, not both")
+	}
+	if outputTemplate != "" || outputFormat != display.FormatTable {
+		display.DisableColor()
+	}
+	return nil
+}
+
+// printProjects writes a project list in the selected output, with cols
+// (nil for the defaults) for the table and the row formats. A template runs
+// once per project.
+func printProjects(projects []*project.Project, cols []display.Column) error {
+	if outputTemplate != "" {
+		items := make([]any, len(projects))
+		for i, p := range projects {
+			items[i] = p
+		}
+		return execTemplate(items...)
+	}
+	if outputFormat == display.FormatTable {
+		display.PrintTable(projects, cols)
+		return nil
+	}
+	return display.WriteProjects(os.Stdout, outputFormat, projects, cols)
+}
+
+// printGroups writes grouped projects in the selected output. A template
+// runs once per group.
+func printGroups(groups []*display.Group, cols []display.Column, sums []string) error {
+	if outputTemplate != "" {
+		items := make([]any, len(groups))
+		for i, g := range groups {
+			items[i] = g
+		}
+		return execTemplate(items...)
+	}
+	if outputFormat == display.FormatTable {
+		display.PrintGroups(groups, cols, sums)
+		return nil
+	}
+	return display.WriteGroups(os.Stdout, outputFormat, groups, cols)
+}
+
+// printProject writes one project in the selected output.
+func printProject(p *project.Project) error {
+	if outputTemplate != "" {
+		return execTemplate(p)
+	}
+	if outputFormat == display.FormatTable {
+		display.PrintDetail(p)
+		return nil
+	}
+	return display.WriteProject(os.Stdout, outputFormat, p)
+}
+
+// printSummary writes the status report in the selected output.
+func printSummary(s *display.Summary) error {
+	if outputTemplate != "" {
+		return execTemplate(s)
+	}
+	if outputFormat == display.FormatTable {
+		display.PrintSummary(s)
+		return nil
+	}
+	return display.WriteSummary(os.Stdout, outputFormat, s)
+}
+
+func execTemplate(items ...any) error {
+	tmpl, err := display.ParseTemplate(outputTemplate)
+	if err != nil {
+		return fmt.Errorf("parse --template: %w", err)
+	}
+	for _, it := range items {
+		if err := display.ExecTemplate(os.Stdout, tmpl, it); err != nil {
+			return fmt.Errorf("run --template: %w", err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", display.FormatTable,
+		"Output format for list, info and status: table, json, jsonl, csv, tsv, yaml, markdown")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "",
+		"Go text/template for list, info and status output, e.g. '{{.Name}}{{\"\\t\"}}{{.Path}}'")
+	rootCmd.RegisterFlagCompletionFunc("output", completeWords(display.Formats...))
+	rootCmd.RegisterFlagCompletionFunc("template", noCompletion)
+	rootCmd.PersistentPreRunE = checkOutputFlags
+}
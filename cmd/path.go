@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/peeomid/prj/internal/frecency"
+	"github.com/spf13/cobra"
+)
+
+var pathRecord bool
+
+var pathCmd = &cobra.Command{
+	Use:   "path <query>",
+	Short: "Print the path of the project matching a query",
+	Long: `Print the directory of the best match for a query, found the same way
+as "prj info" finds a project. Made for scripts and shell functions:
+
+  cd "$(prj path api)"
+
+When several projects match equally, the one you jump into most often
+and most recently with "prj cd" wins. If that doesn't settle it, you
+pick one (when stdin and stderr are a terminal) or get the list of
+candidates.
+
+--record counts the lookup as a jump for that ranking. The function
+from "prj shell-init" passes it; plain lookups don't.
+
+Examples:
+  prj path api               /home/me/work/api
+  prj path work/api          The "api" under a folder named work
+  code "$(prj path myapp)"   Open a project in VS Code`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		projects, err := st.Load()
+		st.Close()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+
+		p, err := resolveProject(projects, args[0])
+		if err != nil {
+			return err
+		}
+		if p.Missing != "" {
+			return fmt.Errorf("%s is missing: %s no longer exists (run prj scan)", p.Name, p.Path)
+		}
+		if pathRecord {
+			if err := frecency.Visit(p.Path, time.Now()); err != nil {
+				return fmt.Errorf("record visit: %w", err)
+			}
+		}
+		fmt.Println(p.Path)
+		return nil
+	},
+}
+
+var cdCmd = &cobra.Command{
+	Use:   "cd <query>",
+	Short: "Jump to a project's directory (needs prj shell-init)",
+	Long: `Change the shell's directory to the project matching a query.
+
+A program can't change its parent shell's directory, so this works
+through the shell function "prj shell-init" prints. Add one of these to
+your shell's startup file:
+
+  eval "$(prj shell-init bash)"      # ~/.bashrc
+  eval "$(prj shell-init zsh)"       # ~/.zshrc
+  prj shell-init fish | source       # ~/.config/fish/config.fish
+
+Examples:
+  prj cd api      Jump to the best match for "api"
+  p api           The same, with the short alias`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeProject,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return fmt.Errorf(`prj cd needs the shell function: add eval "$(prj shell-init bash)" (or zsh, fish) to your shell's startup file`)
+	},
+}
+
+func init() {
+	pathCmd.Flags().BoolVar(&pathRecord, "record", false, `Count this as a jump for ranking (used by "prj cd")`)
+	rootCmd.AddCommand(pathCmd)
+	rootCmd.AddCommand(cdCmd)
+}
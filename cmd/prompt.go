@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptFormat  string
+	promptNoColor bool
+	promptShell   string
+)
+
+const defaultPromptFormat = `{{bold .Name}} {{status .Status}}` +
+	`{{if .Todo}} ✎{{.Todo}}{{end}}` +
+	`{{if .Dirty}} {{yellow "*"}}{{end}}` +
+	`{{if .Unpushed}} {{red (printf "↑%d" .Unpushed)}}{{end}}`
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a short segment about the current project for shell prompts",
+	Long: `Print a one-line segment for the project containing the current
+directory: its name, status (in its color), open TODO count, and
+markers for uncommitted changes (*) and unpushed commits (↑2). Outside
+a stored project it prints nothing.
+
+It never runs git. It reads ~/.prj/index.json, a small index rewritten
+with the store, so it takes a few milliseconds — but shows the repo as
+of the last "prj scan" (or rescan in "prj ui", or "prj here --add").
+
+--format is a Go template over .Name, .Path, .Status, .Mark, .Todo,
+.Dirty and .Unpushed, with the functions status (color a status),
+bold, red, green, yellow, blue, cyan and gray. The default is:
+
+  ` + defaultPromptFormat + `
+
+Colors are on even though the output is captured, unless --no-color
+or NO_COLOR is set. --shell wraps the color codes so bash or zsh can
+tell how wide the prompt is.
+
+Setup:
+  bash      PS1='$(prj prompt --shell bash) \w \$ '
+  zsh       setopt prompt_subst; PROMPT='$(prj prompt --shell zsh) %~ %# '
+  starship  [custom.prj]
+            command = "prj prompt --no-color"
+            when = true
+            style = "bold green"
+  p10k      function prompt_prj() { p10k segment -t "$(prj prompt --no-color)" }
+            and add prj to POWERLEVEL9K_LEFT_PROMPT_ELEMENTS
+
+Examples:
+  prj prompt                                  api active ✎3 * ↑2
+  prj prompt --format '{{.Name}}{{if .Dirty}}*{{end}}'
+  prj prompt --no-color`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmpl, err := template.New("prompt").Funcs(promptFuncs).Parse(promptFormat)
+		if err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		if promptShell != "" && promptShell != "bash" && promptShell != "zsh" {
+			return fmt.Errorf("unknown --shell %q (want bash or zsh)", promptShell)
+		}
+		if promptNoColor {
+			display.DisableColor()
+		} else {
+			display.EnableColor()
+		}
+
+		// A prompt must never get in the way: anything short of a bad
+		// --format just prints nothing.
+		seg, ok := promptSegment()
+		if !ok {
+			return nil
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, seg); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+		fmt.Print(wrapEscapes(b.String(), promptShell))
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", defaultPromptFormat, "Go template for the segment")
+	promptCmd.Flags().BoolVar(&promptNoColor, "no-color", false, "Print without colors")
+	promptCmd.Flags().StringVar(&promptShell, "shell", "", "Wrap color codes for a prompt: bash or zsh")
+	promptCmd.RegisterFlagCompletionFunc("format", noCompletion)
+	promptCmd.RegisterFlagCompletionFunc("shell", completeWords("bash", "zsh"))
+	rootCmd.AddCommand(promptCmd)
+}
+
+// promptData is what --format sees.
+type promptData struct {
+	Name     string
+	Path     string
+	Status   string
+	Mark     string
+	Todo     int
+	Dirty    bool
+	Unpushed int
+}
+
+var promptFuncs = template.FuncMap{
+	"status": display.StatusColor,
+	"bold":   display.Bold,
+	"red":    display.Red,
+	"green":  display.Green,
+	"yellow": display.Yellow,
+	"blue":   display.Blue,
+	"cyan":   display.Cyan,
+	"gray":   display.Gray,
+}
+
+// promptSegment finds the indexed project containing the working
+// directory. The index is built from the store the first time it's
+// missing.
+func promptSegment() (promptData, bool) {
+	idx, err := store.LoadIndex()
+	if os.IsNotExist(err) {
+		if st, err := openStore(); err == nil {
+			projects, _ := st.Load()
+			st.Close()
+			idx, _ = store.BuildIndex(projects)
+		}
+	}
+	if len(idx) == 0 {
+		return promptData{}, false
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return promptData{}, false
+	}
+	for {
+		if e, ok := idx[dir]; ok {
+			return promptData{
+				Name:     e.Name,
+				Path:     dir,
+				Status:   e.Status,
+				Mark:     e.Mark,
+				Todo:     e.TodoOpen,
+				Dirty:    e.Dirty,
+				Unpushed: e.Unpushed,
+			}, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return promptData{}, false
+		}
+		dir = parent
+	}
+}
+
+var escapeSeq = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// wrapEscapes marks color codes as zero-width for bash (readline's \001
+// and \002; \[ \] aren't decoded in command output) or zsh (%{ %}) so line
+// editing keeps track of the cursor. For zsh it also doubles any % in the
+// text, which prompt_subst would otherwise read as a prompt escape.
+func wrapEscapes(s, shell string) string {
+	switch shell {
+	case "bash":
+		return escapeSeq.ReplaceAllString(s, "\x01$0\x02")
+	case "zsh":
+		s = strings.ReplaceAll(s, "%", "%%")
+		return escapeSeq.ReplaceAllString(s, `%{$0%}`)
+	}
+	return s
+}
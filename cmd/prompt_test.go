@@ -0,0 +1,23 @@
+package cmd
+
+import "testing"
+
+func TestWrapEscapes(t *testing.T) {
+	const red, reset = "\x1b[31m", "\x1b[0m"
+	tests := []struct {
+		in, shell, want string
+	}{
+		{"api " + red + "wip" + reset, "bash", "api \x01" + red + "\x02wip\x01" + reset + "\x02"},
+		{"api " + red + "wip" + reset, "zsh", "api %{" + red + "%}wip%{" + reset + "%}"},
+		{"100%-done " + red + "x" + reset, "zsh", "100%%-done %{" + red + "%}x%{" + reset + "%}"},
+		{"%~ %n", "zsh", "%%~ %%n"},
+		{"100% " + red + "x", "bash", "100% \x01" + red + "\x02x"},
+		{"api " + red + "wip" + reset, "", "api " + red + "wip" + reset},
+		{"plain", "zsh", "plain"},
+	}
+	for _, tt := range tests {
+		if got := wrapEscapes(tt.in, tt.shell); got != tt.want {
+			t.Errorf("wrapEscapes(%q, %q) = %q, want %q", tt.in, tt.shell, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var pruneDryRun bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete stored projects whose repo is gone or no longer tracked",
+	Long: `Remove projects from ~/.prj/projects.json when their repository no
+longer exists on disk, is no longer a git repo, or is outside every
+folder in your scan list (for example after "prj remove").
+
+Only the stored data is deleted — nothing on disk is touched.
+
+Examples:
+  prj prune                Delete missing projects from the store
+  prj prune --dry-run      List what would be deleted`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		st, err := store.Open(cfg.Storage)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		projects, err := st.Load()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+
+		store.MarkMissing(projects, cfg.Folders)
+		kept, removed := store.Prune(projects)
+
+		if len(removed) == 0 {
+			fmt.Println("Nothing to prune.")
+			return nil
+		}
+
+		for _, p := range removed {
+			fmt.Printf("  %s  %s  %s\n", p.Name, display.Gray(p.Path), display.Yellow(p.Missing))
+		}
+
+		if pruneDryRun {
+			fmt.Printf("\n%s — %d projects would be removed\n", display.Yellow("dry-run"), len(removed))
+			return nil
+		}
+
+		paths := make([]string, len(removed))
+		for i, p := range removed {
+			paths[i] = p.Path
+		}
+		if err := st.Delete(paths...); err != nil {
+			return fmt.Errorf("save store: %w", err)
+		}
+
+		fmt.Printf("\n%s — removed %d projects, %d left\n", display.Green("done"), len(removed), len(kept))
+		return nil
+	},
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without saving")
+	rootCmd.AddCommand(pruneCmd)
+}
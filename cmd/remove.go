@@ -12,17 +12,24 @@ var removeCmd = &cobra.Command{
 	Short: "Stop tracking a folder (does not delete projects data)",
 	Long: `Remove a folder from the scan list. Future scans will no longer look
 in this folder for repos. Already-scanned projects stay in the data
-file until they are overwritten by a new scan.
+file; the next scan marks them missing and "prj prune" deletes them.
 
 Use "prj config" to see which folders are currently tracked.
 
 Examples:
   prj remove ~/old-projects     Stop scanning this folder
   prj remove ~/Development      Remove your main dev folder`,
-	Args: cobra.ExactArgs(1),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeFolder,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		folder := expandPath(args[0])
 
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
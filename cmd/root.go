@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -27,8 +30,24 @@ Data is stored in ~/.prj/ as JSON files.`,
 }
 
 func Execute() {
+	store.AppVersion = Version
+	cobra.OnInitialize(loadStatusColors)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// loadStatusColors registers the colors of user-defined statuses. A broken
+// config is reported by the command itself, so errors are ignored here.
+func loadStatusColors() {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	for _, r := range cfg.StatusRules {
+		if r.Color != "" {
+			display.SetStatusColor(r.Status, r.Color)
+		}
+	}
+}
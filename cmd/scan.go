@@ -2,16 +2,23 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+	"time"
 
 	"github.com/peeomid/prj/internal/config"
 	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/history"
 	"github.com/peeomid/prj/internal/project"
 	"github.com/peeomid/prj/internal/scanner"
 	"github.com/peeomid/prj/internal/store"
 	"github.com/spf13/cobra"
 )
 
-var scanDryRun bool
+var (
+	scanDryRun bool
+	scanJobs   int
+	scanFull   bool
+)
 
 var scanCmd = &cobra.Command{
 	Use:   "scan",
@@ -22,18 +29,40 @@ and extract rich metadata from each one:
   - Git history: last commit, recent commits, contributor list
   - Tech stack: Ruby, Node, Python, Go, Swift, Rust, and frameworks
   - Project type: rails-app, node-app, go-app, docs, script, etc.
-  - Status: active (< 30d), recent (< 90d), wip, or paused
+  - Status: active, wip, recent, or paused (thresholds in "prj config")
   - Deployment: Docker, Heroku, Vercel, GitHub Actions, etc.
   - Reference files: README, CLAUDE.md, .ai/, .cursor/, docs/, tasks/
   - TODO counts: open/closed items from TODO.md
 
+Repos are extracted in parallel (one worker per CPU by default); use
+--jobs to change that. Results are always saved in the same order,
+no matter which repo finishes first.
+
+Scans are incremental: a repo whose HEAD, index and metadata files are
+unchanged since the last scan keeps its stored data (only its status is
+re-checked). Use --full to re-extract every repo.
+
 Results are merged into ~/.prj/projects.json (existing projects are
-updated, new ones are added).
+updated, new ones are added). Stored projects whose repo was deleted or
+whose folder is no longer tracked are marked missing; remove them with
+"prj prune". A repo moved or renamed between tracked folders is
+recognized by its root commit and remote, and keeps its history.
+
+Each scan also appends a compact snapshot of every project to
+~/.prj/history.jsonl, used by "prj history" and "prj trends", and
+logs what changed since the previous scan (new and removed projects,
+status flips, new tech, new contributors, TODO counts) for "prj changes".
 
 Examples:
   prj scan               Scan and save all project data
-  prj scan --dry-run     Scan but don't save (preview what would happen)`,
+  prj scan --dry-run     Scan but don't save (preview what would happen)
+  prj scan --jobs 1      Extract one repo at a time
+  prj scan --full        Re-extract every repo, even unchanged ones`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if scanJobs < 1 {
+			return fmt.Errorf("--jobs must be at least 1")
+		}
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
@@ -43,6 +72,11 @@ Examples:
 			return fmt.Errorf("no folders configured. Run: prj add <folder>")
 		}
 
+		classifier, err := project.NewClassifier(cfg)
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+
 		var allRepos []string
 		for _, folder := range cfg.Folders {
 			fmt.Printf("Scanning %s ...\n", folder)
@@ -60,35 +94,105 @@ Examples:
 			return nil
 		}
 
-		fmt.Printf("\nExtracting metadata from %d repos...\n", len(allRepos))
-		var scanned []*project.Project
-		for i, repoPath := range allRepos {
-			fmt.Printf("  [%d/%d] %s\n", i+1, len(allRepos), repoPath)
-			p := project.ExtractFromPath(repoPath)
-			scanned = append(scanned, p)
+		st, err := store.Open(cfg.Storage)
+		if err != nil {
+			return err
 		}
+		defer st.Close()
+
+		existing, err := st.Load()
+		if err != nil {
+			return fmt.Errorf("load store: %w", err)
+		}
+
+		prev := make(map[string]*project.Project, len(existing))
+		for _, p := range existing {
+			prev[p.Path] = p
+		}
+
+		fmt.Printf("\nExtracting metadata from %d repos...\n", len(allRepos))
+		reused := 0
+		scanned := project.ExtractAll(allRepos, scanJobs, classifier, prev, scanFull, func(done int, repoPath string, unchanged bool) {
+			if unchanged {
+				reused++
+				fmt.Printf("  [%d/%d] %s %s\n", done, len(allRepos), repoPath, display.Gray("(unchanged)"))
+				return
+			}
+			fmt.Printf("  [%d/%d] %s\n", done, len(allRepos), repoPath)
+		})
+		fmt.Printf("\n%d re-extracted, %d unchanged\n", len(scanned)-reused, reused)
 
 		if scanDryRun {
 			fmt.Printf("\n%s — %d projects scanned (not saved)\n", display.Yellow("dry-run"), len(scanned))
 			return nil
 		}
 
-		existing, err := store.Load()
+		// Extraction can take a while; only hold the lock for the merge, and
+		// re-read the store in case another prj wrote it in the meantime.
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		existing, err = st.Load()
 		if err != nil {
 			return fmt.Errorf("load store: %w", err)
 		}
 
-		merged := store.Merge(existing, scanned)
-		if err := store.Save(merged); err != nil {
+		// Diff against copies: MarkMissing below updates stored projects in place.
+		before := make([]*project.Project, len(existing))
+		for i, p := range existing {
+			c := *p
+			before[i] = &c
+		}
+
+		merged, moved := store.Merge(existing, scanned)
+		// Moved repos only now inherit their annotations; classify them again.
+		for _, p := range moved {
+			classifier.Classify(p.Path, p)
+		}
+		missing := store.MarkMissing(merged, cfg.Folders)
+		if err := st.Save(merged); err != nil {
 			return fmt.Errorf("save store: %w", err)
 		}
 
-		fmt.Printf("\n%s — %d projects saved to %s\n", display.Green("done"), len(merged), store.Path())
+		now := time.Now().UTC().Format(time.RFC3339)
+		if _, err := history.Append(history.FromProjects(now, merged)); err != nil {
+			fmt.Printf("  %s: record history: %s\n", display.Red("error"), err)
+		}
+		if cfg.HistoryDays > 0 {
+			if _, err := history.Trim(time.Now().AddDate(0, 0, -cfg.HistoryDays)); err != nil {
+				fmt.Printf("  %s: trim history: %s\n", display.Red("error"), err)
+			}
+		}
+		changes := history.Diff(now, before, merged)
+		if err := history.AppendChanges(changes); err != nil {
+			fmt.Printf("  %s: record changes: %s\n", display.Red("error"), err)
+		}
+		if err := history.RecordScan(now); err != nil {
+			fmt.Printf("  %s: record scan: %s\n", display.Red("error"), err)
+		}
+
+		for _, p := range moved {
+			last := p.Moves[len(p.Moves)-1]
+			fmt.Printf("%s %s: %s → %s\n", display.Cyan("moved"), p.Name, last.From, last.To)
+		}
+
+		fmt.Printf("\n%s — %d projects saved to %s\n", display.Green("done"), len(merged), st.Location())
+		if len(changes) > 0 {
+			fmt.Printf("%d changes since the last scan. Run: prj changes\n", len(changes))
+		}
+		if missing > 0 {
+			fmt.Printf("%s — %d projects no longer exist or are outside tracked folders. Run: prj prune\n", display.Yellow("missing"), missing)
+		}
 		return nil
 	},
 }
 
 func init() {
 	scanCmd.Flags().BoolVar(&scanDryRun, "dry-run", false, "Scan without saving")
+	scanCmd.Flags().BoolVar(&scanFull, "full", false, "Re-extract every repo, ignoring unchanged fingerprints")
+	scanCmd.Flags().IntVarP(&scanJobs, "jobs", "j", runtime.NumCPU(), "Number of repos to extract in parallel")
 	rootCmd.AddCommand(scanCmd)
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var shellInitAlias string
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init bash|zsh|fish",
+	Short: "Print the shell function behind prj cd",
+	Long: `Print shell code that wraps prj in a function, so "prj cd <query>"
+can change the current directory. It also defines a short alias, p,
+for "prj cd". Every other prj command passes straight through.
+
+Add it to your shell's startup file:
+
+  eval "$(prj shell-init bash)"      # ~/.bashrc
+  eval "$(prj shell-init zsh)"       # ~/.zshrc
+  prj shell-init fish | source       # ~/.config/fish/config.fish
+
+Each jump is recorded in ~/.prj/frecency.json, so when a query matches
+several projects equally well, the one you visit most wins.
+
+Examples:
+  prj shell-init zsh              Print the zsh function
+  prj shell-init bash --alias j   Use j instead of p
+  prj shell-init fish --alias ""  No short alias`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shellInitAlias != "" && !shellName.MatchString(shellInitAlias) {
+			return fmt.Errorf("invalid --alias %q: use letters, digits, - and _", shellInitAlias)
+		}
+		var script string
+		switch args[0] {
+		case "bash", "zsh":
+			script = posixInit
+			if shellInitAlias != "" {
+				script += fmt.Sprintf("\n%s() {\n  prj cd \"$@\"\n}\n", shellInitAlias)
+			}
+		case "fish":
+			script = fishInit
+			if shellInitAlias != "" {
+				script += fmt.Sprintf("\nfunction %s\n    prj cd $argv\nend\n", shellInitAlias)
+			}
+		default:
+			return fmt.Errorf("unsupported shell %q (want bash, zsh or fish)", args[0])
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+var shellName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+var posixInit = strings.TrimLeft(`
+# prj shell integration: eval "$(prj shell-init bash)"
+prj() {
+  if [ "$1" = cd ]; then
+    shift
+    local dir
+    dir="$(command prj path --record "$@")" && builtin cd -- "$dir"
+  else
+    command prj "$@"
+  fi
+}
+`, "\n")
+
+var fishInit = strings.TrimLeft(`
+# prj shell integration: prj shell-init fish | source
+function prj
+    if test (count $argv) -gt 0; and test "$argv[1]" = cd
+        set -l dir (command prj path --record $argv[2..-1]); and builtin cd -- $dir
+    else
+        command prj $argv
+    end
+end
+`, "\n")
+
+func init() {
+	shellInitCmd.Flags().StringVar(&shellInitAlias, "alias", "p", `Name of the short alias for "prj cd" ("" for none)`)
+	shellInitCmd.RegisterFlagCompletionFunc("alias", noCompletion)
+	rootCmd.AddCommand(shellInitCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/peeomid/prj/internal/config"
 	"github.com/peeomid/prj/internal/display"
 	"github.com/peeomid/prj/internal/store"
 	"github.com/spf13/cobra"
@@ -17,21 +18,37 @@ var statusCmd = &cobra.Command{
   - Breakdown by status (active, wip, recent, paused)
   - Breakdown by type (rails-app, node-app, go-app, etc.)
   - Ownership split (your own repos vs forks)
+  - Missing projects waiting for "prj prune"
+  - Focus and maintained projects (see "prj mark")
   - Top 5 most recently committed projects
-  - Stalled projects (no commits in 6+ months)
+  - Stalled projects (no commits in stalled_days, 180 by default),
+    leaving out archived, abandoned, idea and maintained ones
 
 Run "prj scan" first to populate the data.
 
 Examples:
-  prj status                Show the full summary report`,
+  prj status                Show the full summary report
+  prj status -o json        The same numbers as JSON (or yaml, csv, ...)
+  prj status --template '{{.Total}} projects, {{len .Stalled}} stalled'`,
+	Annotations: outputCommands,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		projects, err := store.Load()
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		st, err := store.Open(cfg.Storage)
+		if err != nil {
+			return fmt.Errorf("open store: %w", err)
+		}
+		defer st.Close()
+
+		projects, err := st.Load()
 		if err != nil {
 			return fmt.Errorf("load projects: %w", err)
 		}
 
-		display.PrintSummary(projects)
-		return nil
+		return printSummary(display.Summarize(projects, cfg))
 	},
 }
 
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/peeomid/prj/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	storeMigrateDryRun bool
+	storeConvertTo     string
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the on-disk project store (schema, backend)",
+	Long: `Manage where and how scanned projects are stored.
+
+Two backends are available, chosen by "storage" in ~/.prj/config.json:
+
+  json     ~/.prj/projects.json — one file, easy to read and diff (default)
+  sqlite   ~/.prj/projects.db   — embedded database, faster for large fleets`,
+}
+
+var storeMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade projects.json to the current schema version",
+	Long: `Upgrade ~/.prj/projects.json to the schema version this prj
+understands. Older files are upgraded in memory on every load anyway;
+this command rewrites the file once and keeps a backup of the original
+next to it (projects.json.v<N>.bak).
+
+This applies to the JSON backend. The SQLite backend upgrades itself
+whenever it is opened.
+
+A file written by a newer prj is never downgraded — upgrade prj instead.
+
+Examples:
+  prj store migrate --dry-run   Show which migrations would run
+  prj store migrate             Upgrade the file in place`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if cfg.Storage == store.BackendSQLite {
+			st, err := store.Open(cfg.Storage)
+			if err != nil {
+				return fmt.Errorf("open store: %w", err)
+			}
+			defer st.Close()
+			fmt.Printf("%s is up to date (schema v%d)\n", st.Location(), store.SchemaVersion)
+			return nil
+		}
+
+		from, err := store.FileVersion()
+		if err != nil {
+			return fmt.Errorf("read store: %w", err)
+		}
+		if from == 0 {
+			fmt.Println("No store yet. Run: prj scan")
+			return nil
+		}
+
+		pending, err := store.Pending(from)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Printf("%s is up to date (schema v%d)\n", store.Path(), from)
+			return nil
+		}
+
+		fmt.Printf("%s is schema v%d, current is v%d\n", store.Path(), from, store.SchemaVersion)
+		for _, m := range pending {
+			fmt.Printf("  v%d → v%d  %s\n", m.From, m.From+1, m.Description)
+		}
+
+		if storeMigrateDryRun {
+			fmt.Printf("\n%s — nothing written\n", display.Yellow("dry-run"))
+			return nil
+		}
+
+		backup, err := store.Migrate()
+		if err != nil {
+			return fmt.Errorf("migrate store: %w", err)
+		}
+		fmt.Printf("\n%s — upgraded to v%d, backup at %s\n", display.Green("done"), store.SchemaVersion, backup)
+		return nil
+	},
+}
+
+var storeConvertCmd = &cobra.Command{
+	Use:   "convert --to json|sqlite",
+	Short: "Copy all projects to another storage backend and switch to it",
+	Long: `Copy every stored project from the current backend into another one,
+then set "storage" in the config so later commands use it. The old
+file is left in place as a backup.
+
+Examples:
+  prj store convert --to sqlite   Move from projects.json to projects.db
+  prj store convert --to json     Move back to projects.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		from := cfg.Storage
+		if from == "" {
+			from = store.BackendJSON
+		}
+		if from == storeConvertTo {
+			return fmt.Errorf("already using the %s backend", from)
+		}
+
+		src, err := store.Open(from)
+		if err != nil {
+			return fmt.Errorf("open %s store: %w", from, err)
+		}
+		defer src.Close()
+
+		dst, err := store.Open(storeConvertTo)
+		if err != nil {
+			return fmt.Errorf("open %s store: %w", storeConvertTo, err)
+		}
+		defer dst.Close()
+
+		n, err := store.Copy(src, dst)
+		if err != nil {
+			return err
+		}
+
+		cfg.Storage = storeConvertTo
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+
+		fmt.Printf("%s — copied %d projects from %s to %s\n", display.Green("done"), n, src.Location(), dst.Location())
+		return nil
+	},
+}
+
+func init() {
+	storeMigrateCmd.Flags().BoolVar(&storeMigrateDryRun, "dry-run", false, "Show pending migrations without writing")
+	storeConvertCmd.Flags().StringVar(&storeConvertTo, "to", "", "Target backend: json or sqlite")
+	storeConvertCmd.MarkFlagRequired("to")
+	storeConvertCmd.RegisterFlagCompletionFunc("to", completeWords(store.BackendJSON, store.BackendSQLite))
+	storeCmd.AddCommand(storeMigrateCmd)
+	storeCmd.AddCommand(storeConvertCmd)
+	rootCmd.AddCommand(storeCmd)
+}
+
+// openStore opens the storage backend selected in the config.
+func openStore() (store.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	st, err := store.Open(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	return st, nil
+}
+
+// updateProject finds the project matching query and saves the changes fn
+// makes to it. The match (which may prompt) happens before taking the
+// config lock; the store is then re-read under the lock, so a concurrent
+// scan can't drop the changes and nobody waits on the prompt. Status rules
+// can read annotations, so the project is classified again before saving.
+func updateProject(query string, fn func(p *project.Project) error) (*project.Project, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	classifier, err := project.NewClassifier(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	st, err := store.Open(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	defer st.Close()
+
+	projects, err := st.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load projects: %w", err)
+	}
+	picked, err := resolveProject(projects, query)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := config.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	projects, err = st.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load projects: %w", err)
+	}
+	p := findSame(projects, picked)
+	if p == nil {
+		return nil, fmt.Errorf("project %s was removed meanwhile", picked.Path)
+	}
+	if err := fn(p); err != nil {
+		return nil, err
+	}
+	// A missing repo has no TODO.md to re-read; it keeps its last status.
+	if p.Missing == "" {
+		classifier.Classify(p.Path, p)
+	}
+	if err := st.Upsert(p); err != nil {
+		return nil, fmt.Errorf("save store: %w", err)
+	}
+	return p, nil
+}
+
+// findSame returns the project in projects that is old: the one at the
+// same path, or failing that (a scan saw it move) the one with its ID.
+func findSame(projects []*project.Project, old *project.Project) *project.Project {
+	for _, p := range projects {
+		if p.Path == old.Path {
+			return p
+		}
+	}
+	if old.ID == "" {
+		return nil
+	}
+	for _, p := range projects {
+		if p.ID == old.ID {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add, remove and list project tags",
+	Long: `Tags are free-form labels you attach to projects — a client name,
+"needs-upgrade", "demo". They are kept across scans and can be
+filtered with "prj list --tag" or found with "prj list --search".
+
+Examples:
+  prj tag add myapp acme needs-upgrade   Tag myapp twice
+  prj tag rm myapp needs-upgrade         Remove a tag
+  prj tag ls                             Every tag with its project count
+  prj list --tag acme                    Projects tagged acme`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:               "add <name> <tag>...",
+	Short:             "Add tags to a project",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeTags(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var added []string
+		p, err := updateProject(args[0], func(p *project.Project) error {
+			added = p.AddTags(args[1:]...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			fmt.Printf("%s already has those tags\n", p.Name)
+			return nil
+		}
+		fmt.Printf("Tagged %s: %s\n", p.Name, display.Cyan(strings.Join(p.Tags, ", ")))
+		return nil
+	},
+}
+
+var tagRmCmd = &cobra.Command{
+	Use:               "rm <name> <tag>...",
+	Aliases:           []string{"remove"},
+	Short:             "Remove tags from a project",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeTags(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var removed []string
+		p, err := updateProject(args[0], func(p *project.Project) error {
+			removed = p.RemoveTags(args[1:]...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Printf("%s has none of those tags\n", p.Name)
+			return nil
+		}
+		fmt.Printf("Removed %s from %s\n", strings.Join(removed, ", "), p.Name)
+		return nil
+	},
+}
+
+var tagLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List every tag in use with its project count",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		projects, err := st.Load()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+
+		counts := map[string]int{}
+		for _, p := range projects {
+			for _, t := range p.Tags {
+				counts[strings.ToLower(t)]++
+			}
+		}
+		if len(counts) == 0 {
+			fmt.Println("No tags yet. Add one with: prj tag add <name> <tag>")
+			return nil
+		}
+		tags := make([]string, 0, len(counts))
+		for t := range counts {
+			tags = append(tags, t)
+		}
+		sort.Strings(tags)
+		for _, t := range tags {
+			fmt.Printf("  %-24s %d\n", display.Cyan(t), counts[t])
+		}
+		return nil
+	},
+}
+
+// completeTags completes the project, then tags: the project's own for
+// removal (own), otherwise every known tag it doesn't have yet.
+func completeTags(own bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeProject(cmd, args, toComplete)
+		}
+		projects := completionProjects()
+		p, _ := project.Resolve(projects, args[0])
+		if p == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		candidates := knownTags(projects)
+		if own {
+			candidates = p.Tags
+		}
+		var out []string
+		for _, t := range matching(candidates, toComplete) {
+			if own != p.HasTag(t) || containsFold(args[1:], t) {
+				continue
+			}
+			out = append(out, t)
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRmCmd)
+	tagCmd.AddCommand(tagLsCmd)
+	rootCmd.AddCommand(tagCmd)
+}
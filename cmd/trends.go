@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var trendsBy string
+
+var trendsCmd = &cobra.Command{
+	Use:   "trends",
+	Short: "Fleet-wide report of project counts, statuses and commits over time",
+	Long: `Summarize scan history across all projects, one row per period:
+how many projects there were, how many were active/wip/recent/paused,
+total commits (8 months), open TODOs, and the most common tech.
+
+Each period is reported as of its last scan. Scans that change nothing
+aren't recorded, so a period without any repeats the one before.
+
+Examples:
+  prj trends                 One row per month
+  prj trends --by quarter    How many projects were active last quarter?
+  prj trends --by week       Finer-grained`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scans, err := history.Load()
+		if err != nil {
+			return fmt.Errorf("load history: %w", err)
+		}
+
+		trends, err := history.Trends(scans, trendsBy)
+		if err != nil {
+			return err
+		}
+
+		display.PrintTrends(trends)
+		return nil
+	},
+}
+
+func init() {
+	trendsCmd.Flags().StringVar(&trendsBy, "by", "month", "Group by: week, month, quarter")
+	trendsCmd.RegisterFlagCompletionFunc("by", completeWords("week", "month", "quarter"))
+	rootCmd.AddCommand(trendsCmd)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/peeomid/prj/internal/tui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var uiSort string
+
+var uiCmd = &cobra.Command{
+	Use:   "ui [query]",
+	Short: "Browse projects in a full-screen terminal UI",
+	Long: `Open a full-screen browser over the stored projects: a list on the
+left, the same details "prj info" prints on the right (or on their own
+screen in narrow terminals, with enter).
+
+Typing "/" starts a live fuzzy search using the same ranking as
+"prj info"; enter keeps the filter, esc clears it.
+
+Keys:
+  ↑/↓ j/k     Move (scroll the details when they fill the screen)
+  pgup/pgdn   Move a page
+  /           Search
+  s           Cycle the sort order: date, name, commits
+  enter       Show or hide the full-screen details
+  e           Open the project in $VISUAL or $EDITOR
+  c, y        Copy the project's path to the clipboard
+  r           Rescan just this repo and save it
+  q, esc      Quit
+
+Copying uses pbcopy, wl-copy, xclip or xsel, whichever is installed,
+and otherwise asks the terminal to do it (OSC 52).
+
+Examples:
+  prj ui                Browse everything, most recent first
+  prj ui api            Start with a search for "api"
+  prj ui --sort name    Start sorted by name`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !interactive() {
+			return fmt.Errorf("prj ui needs a terminal; use prj list instead")
+		}
+		if !validSortKey(uiSort) {
+			return fmt.Errorf("unknown sort %q (want %s)", uiSort, strings.Join(project.SortKeys, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		classifier, err := project.NewClassifier(cfg)
+		if err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		projects, err := st.Load()
+		st.Close()
+		if err != nil {
+			return fmt.Errorf("load projects: %w", err)
+		}
+		if len(projects) == 0 {
+			fmt.Println("No projects found. Run: prj scan")
+			return nil
+		}
+
+		opts := tui.Options{
+			Projects: projects,
+			Sort:     uiSort,
+			Rescan: func(p *project.Project) (*project.Project, error) {
+				return rescanProject(p, classifier)
+			},
+			Editor: editorCommand,
+			Copy:   copyToClipboard,
+		}
+		if len(args) == 1 {
+			opts.Query = args[0]
+		}
+		return tui.Run(opts)
+	},
+}
+
+func init() {
+	uiCmd.Flags().StringVar(&uiSort, "sort", "date", "Initial sort: "+strings.Join(project.SortKeys, ", "))
+	uiCmd.RegisterFlagCompletionFunc("sort", completeWords(project.SortKeys...))
+	rootCmd.AddCommand(uiCmd)
+}
+
+// interactive reports whether we own a terminal: stdin and stdout are
+// both one.
+func interactive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func validSortKey(key string) bool {
+	for _, k := range project.SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// rescanProject re-extracts one repo and saves it, keeping the annotations
+// stored for it. The stored copy is re-read under the lock so a note or tag
+// added meanwhile isn't lost.
+func rescanProject(p *project.Project, c *project.Classifier) (*project.Project, error) {
+	if _, err := os.Stat(p.Path); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Path, err)
+	}
+	fresh := project.ExtractFromPath(p.Path)
+
+	unlock, err := config.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	st, err := openStore()
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+
+	projects, err := st.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load projects: %w", err)
+	}
+	old := p
+	for _, q := range projects {
+		if q.Path == p.Path {
+			old = q
+		}
+	}
+	fresh.Inherit(old)
+	c.Classify(fresh.Path, fresh)
+	if err := st.Upsert(fresh); err != nil {
+		return nil, fmt.Errorf("save store: %w", err)
+	}
+	return fresh, nil
+}
+
+// clipboards are tried in order; the first one installed wins.
+var clipboards = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+// copyToClipboard puts text on the system clipboard, falling back to the
+// OSC 52 escape sequence, which most terminals (and tmux) honor even over
+// SSH.
+func copyToClipboard(text string) error {
+	for _, args := range clipboards {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		c := exec.Command(args[0], args[1:]...)
+		c.Stdin = strings.NewReader(text)
+		return c.Run()
+	}
+	_, err := fmt.Fprintf(os.Stderr, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var viewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Short: "Save and reuse prj list filter/sort combinations",
+	Long: `A view is a named set of "prj list" flags kept in ~/.prj/config.json.
+Run it with "prj view <name>" or "prj list @<name>"; flags given next
+to "@<name>" override the view's.
+
+Examples:
+  prj view save daily --status active --own --tech go --sort commits
+  prj view daily                          Same as: prj list @daily
+  prj list @daily --sort name             Run the view, sorted by name
+  prj view ls                             Show saved views
+  prj view rm daily                       Delete a view`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeView(""),
+	Annotations:       outputCommands,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return viewLsCmd.RunE(viewLsCmd, nil)
+		}
+		if err := applyView(listCmd, "@"+strings.TrimPrefix(args[0], "@")); err != nil {
+			return err
+		}
+		return runList(currentListOptions())
+	},
+}
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save <name> <list flags...>",
+	Short: "Save prj list flags under a name",
+	// The flags belong to "prj list", so they're checked against its flag
+	// set rather than parsed here.
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, a := range args {
+			if a == "-h" || a == "--help" {
+				return cmd.Help()
+			}
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("usage: prj view save <name> <list flags...>")
+		}
+		name := strings.TrimPrefix(args[0], "@")
+		if err := validViewName(name); err != nil {
+			return err
+		}
+		flags := args[1:]
+		if err := checkListFlags(flags); err != nil {
+			return err
+		}
+
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		_, existed := cfg.Views[name]
+		if cfg.Views == nil {
+			cfg.Views = map[string]config.View{}
+		}
+		cfg.Views[name] = config.View{Args: flags}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+
+		verb := "Saved"
+		if existed {
+			verb = "Updated"
+		}
+		fmt.Printf("%s view %s: prj list %s\n", verb, display.Bold("@"+name), shellJoin(flags))
+		return nil
+	},
+}
+
+var viewLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List saved views",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if len(cfg.Views) == 0 {
+			fmt.Println("No saved views. Save one with: prj view save <name> <list flags...>")
+			return nil
+		}
+		names := make([]string, 0, len(cfg.Views))
+		for n := range cfg.Views {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Printf("  %-20s %s\n", display.Bold("@"+n), display.Gray(shellJoin(cfg.Views[n].Args)))
+		}
+		return nil
+	},
+}
+
+var viewRmCmd = &cobra.Command{
+	Use:               "rm <name>",
+	Aliases:           []string{"remove"},
+	Short:             "Delete a saved view",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeView(""),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], "@")
+
+		unlock, err := config.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if _, ok := cfg.Views[name]; !ok {
+			return fmt.Errorf("no saved view %q (see: prj view ls)", name)
+		}
+		delete(cfg.Views, name)
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("Deleted view %s\n", name)
+		return nil
+	},
+}
+
+// applyView loads the view named by arg ("@name") into cmd's flags. Flags
+// already set on the command line win over the view's.
+func applyView(cmd *cobra.Command, arg string) error {
+	name, ok := strings.CutPrefix(arg, "@")
+	if !ok {
+		return fmt.Errorf("unexpected argument %q (saved views are written @name)", arg)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	v, ok := cfg.Views[name]
+	if !ok {
+		return fmt.Errorf("no saved view %q (see: prj view ls)", name)
+	}
+
+	cmd.InheritedFlags() // merges --output and --template into cmd.Flags()
+	explicit := map[string]func(){}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			vals := sv.GetSlice()
+			explicit[f.Name] = func() { sv.Replace(vals) }
+			return
+		}
+		val := f.Value.String()
+		explicit[f.Name] = func() { f.Value.Set(val) }
+	})
+	if err := cmd.Flags().Parse(v.Args); err != nil {
+		return fmt.Errorf("view %s: %w", name, err)
+	}
+	for _, restore := range explicit {
+		restore()
+	}
+	// The view may have picked an output format.
+	return checkOutputFlags(cmd, nil)
+}
+
+// checkListFlags reports whether args parse as "prj list" flags. "view
+// save" never runs list, so parsing into its flag set is harmless.
+func checkListFlags(args []string) error {
+	listCmd.InheritedFlags() // merges --output and --template into listCmd.Flags()
+	fs := listCmd.Flags()
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("not a prj list flag: %w", err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q (a view holds prj list flags only)", fs.Arg(0))
+	}
+	if listFilter.where != "" {
+		if _, err := compileWhere(listFilter.where); err != nil {
+			return err
+		}
+	}
+	return display.CheckFormat(outputFormat)
+}
+
+func validViewName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t/") {
+		return fmt.Errorf("invalid view name %q", name)
+	}
+	for _, c := range viewCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return fmt.Errorf("%q is a prj view subcommand; pick another name", name)
+		}
+	}
+	return nil
+}
+
+// shellJoin renders args as they'd be typed, quoting where needed.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t'\"$`\\()<>|&;*?!~") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+func init() {
+	viewCmd.AddCommand(viewSaveCmd)
+	viewCmd.AddCommand(viewLsCmd)
+	viewCmd.AddCommand(viewRmCmd)
+	rootCmd.AddCommand(viewCmd)
+}
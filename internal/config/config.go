@@ -4,20 +4,134 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/peeomid/prj/internal/fsutil"
 )
 
 type Config struct {
-	Folders    []string `json:"folders"`
-	CutoffDays int      `json:"cutoff_days"`
+	Folders          []string              `json:"folders"`
+	CutoffDays       int                   `json:"cutoff_days"`
+	ActiveDays       int                   `json:"active_days"`
+	StalledDays      int                   `json:"stalled_days"`
+	WIPKeywords      []string              `json:"wip_keywords"`
+	FolderThresholds map[string]Thresholds `json:"folder_thresholds,omitempty"`
+	StatusRules      []StatusRule          `json:"status_rules,omitempty"`
+	Storage          string                `json:"storage,omitempty"`
+	Views            map[string]View       `json:"views,omitempty"`
+	// Columns of the "prj list" table when --columns isn't given.
+	Columns []string `json:"columns,omitempty"`
+	// HistoryDays is how long scans are kept in history.jsonl; negative
+	// keeps them forever.
+	HistoryDays int `json:"history_days,omitempty"`
+}
+
+// Thresholds decide a project's status. In a config file every field is
+// optional: zero values fall back to the global settings.
+type Thresholds struct {
+	// ActiveDays: committed within this many days is "active" (or "wip").
+	ActiveDays int `json:"active_days,omitempty"`
+	// CutoffDays: no commits for this many days is "paused"; in between
+	// ActiveDays and CutoffDays is "recent".
+	CutoffDays int `json:"cutoff_days,omitempty"`
+	// StalledDays: no commits for this many days is listed as stalled in
+	// "prj status".
+	StalledDays int `json:"stalled_days,omitempty"`
+	// WIPKeywords: an active project with one of these (case-insensitive)
+	// in a recent commit message is "wip".
+	WIPKeywords []string `json:"wip_keywords,omitempty"`
 }
 
+// StatusRule assigns a custom status to projects matching an expression
+// over project fields, e.g. "days_since_commit < 14 && todo_open > 0".
+// Rules are tried in order after the built-in status is inferred; the
+// first match wins.
+type StatusRule struct {
+	Status string `json:"status"`
+	When   string `json:"when"`
+	// Color for the status in tables: green, blue, yellow, gray, red, cyan,
+	// magenta or white. Optional.
+	Color string `json:"color,omitempty"`
+}
+
+// View is a saved "prj list" invocation — its filter, sort and display
+// flags — replayed by "prj list @name" or "prj view name".
+type View struct {
+	Args []string `json:"args"`
+}
+
+const (
+	DefaultCutoffDays  = 240
+	DefaultActiveDays  = 30
+	DefaultStalledDays = 180
+	DefaultHistoryDays = 730
+)
+
+var DefaultWIPKeywords = []string{"wip", "work in progress"}
+
 func DefaultConfig() *Config {
 	return &Config{
-		Folders:    []string{},
-		CutoffDays: 240,
+		Folders:     []string{},
+		CutoffDays:  DefaultCutoffDays,
+		ActiveDays:  DefaultActiveDays,
+		StalledDays: DefaultStalledDays,
+		WIPKeywords: DefaultWIPKeywords,
+		HistoryDays: DefaultHistoryDays,
 	}
 }
 
+// Thresholds returns the global status thresholds.
+func (c *Config) Thresholds() Thresholds {
+	return Thresholds{
+		ActiveDays:  c.ActiveDays,
+		CutoffDays:  c.CutoffDays,
+		StalledDays: c.StalledDays,
+		WIPKeywords: c.WIPKeywords,
+	}
+}
+
+// ThresholdsFor returns the thresholds for a repo: the global ones,
+// overridden by the entry in folder_thresholds for the closest folder
+// containing path.
+func (c *Config) ThresholdsFor(path string) Thresholds {
+	th := c.Thresholds()
+
+	path = filepath.Clean(path)
+	best, bestLen := "", 0
+	for folder := range c.FolderThresholds {
+		f := filepath.Clean(expandHome(folder))
+		if (path == f || strings.HasPrefix(path, strings.TrimSuffix(f, string(os.PathSeparator))+string(os.PathSeparator))) && len(f) > bestLen {
+			best, bestLen = folder, len(f)
+		}
+	}
+	if best == "" {
+		return th
+	}
+
+	o := c.FolderThresholds[best]
+	if o.ActiveDays > 0 {
+		th.ActiveDays = o.ActiveDays
+	}
+	if o.CutoffDays > 0 {
+		th.CutoffDays = o.CutoffDays
+	}
+	if o.StalledDays > 0 {
+		th.StalledDays = o.StalledDays
+	}
+	if len(o.WIPKeywords) > 0 {
+		th.WIPKeywords = o.WIPKeywords
+	}
+	return th
+}
+
+func expandHome(p string) string {
+	if strings.HasPrefix(p, "~/") || p == "~" {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, p[1:])
+	}
+	return p
+}
+
 func Dir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".prj")
@@ -27,6 +141,21 @@ func Path() string {
 	return filepath.Join(Dir(), "config.json")
 }
 
+// LockPath is the advisory lock file shared by everything under Dir.
+func LockPath() string {
+	return filepath.Join(Dir(), "prj.lock")
+}
+
+// Lock serializes writers to ~/.prj across processes. Hold it around a
+// load-modify-save so concurrent prj invocations don't lose each other's
+// changes. Save functions take it too, so it is re-entrant.
+func Lock() (func(), error) {
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return nil, err
+	}
+	return fsutil.Lock(LockPath())
+}
+
 func Load() (*Config, error) {
 	cfg := DefaultConfig()
 	data, err := os.ReadFile(Path())
@@ -40,20 +169,35 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	if cfg.CutoffDays == 0 {
-		cfg.CutoffDays = 240
+		cfg.CutoffDays = DefaultCutoffDays
+	}
+	if cfg.ActiveDays == 0 {
+		cfg.ActiveDays = DefaultActiveDays
+	}
+	if cfg.StalledDays == 0 {
+		cfg.StalledDays = DefaultStalledDays
+	}
+	if cfg.HistoryDays == 0 {
+		cfg.HistoryDays = DefaultHistoryDays
+	}
+	if len(cfg.WIPKeywords) == 0 {
+		cfg.WIPKeywords = DefaultWIPKeywords
 	}
 	return cfg, nil
 }
 
 func Save(cfg *Config) error {
-	if err := os.MkdirAll(Dir(), 0755); err != nil {
+	unlock, err := Lock()
+	if err != nil {
 		return err
 	}
+	defer unlock()
+
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(Path(), data, 0644)
+	return fsutil.WriteFileAtomic(Path(), data, 0644)
 }
 
 func (c *Config) AddFolder(folder string) bool {
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestThresholdsFor(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	cfg.FolderThresholds = map[string]Thresholds{
+		"/dev/work/":       {StalledDays: 60},
+		"/dev/work/./team": {StalledDays: 30, ActiveDays: 7},
+		"~/side":           {CutoffDays: 90},
+	}
+
+	tests := []struct {
+		path                    string
+		active, cutoff, stalled int
+	}{
+		{"/dev/work", DefaultActiveDays, DefaultCutoffDays, 60},
+		{"/dev/work/api", DefaultActiveDays, DefaultCutoffDays, 60},
+		{"/dev/work/team/api/", 7, DefaultCutoffDays, 30},
+		{"/dev/workshop/api", DefaultActiveDays, DefaultCutoffDays, DefaultStalledDays},
+		{filepath.Join(home, "side", "x"), DefaultActiveDays, 90, DefaultStalledDays},
+		{"/elsewhere", DefaultActiveDays, DefaultCutoffDays, DefaultStalledDays},
+	}
+	for _, tt := range tests {
+		th := cfg.ThresholdsFor(tt.path)
+		if th.ActiveDays != tt.active || th.CutoffDays != tt.cutoff || th.StalledDays != tt.stalled {
+			t.Errorf("ThresholdsFor(%q) = active %d, cutoff %d, stalled %d; want %d, %d, %d",
+				tt.path, th.ActiveDays, th.CutoffDays, th.StalledDays, tt.active, tt.cutoff, tt.stalled)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package display
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/peeomid/prj/internal/history"
+)
+
+// PrintChanges renders the change feed grouped by scan.
+func PrintChanges(changes []history.Change) {
+	if len(changes) == 0 {
+		fmt.Println("No changes.")
+		return
+	}
+
+	lastAt := ""
+	for _, c := range changes {
+		if c.At != lastAt {
+			fmt.Printf("\n  %s\n", Bold(formatScanTime(c.At)))
+			lastAt = c.At
+		}
+		fmt.Printf("    %s %-25s %s\n", changeLabel(c.Kind), c.Name, changeText(c))
+	}
+	fmt.Println()
+}
+
+func changeLabel(kind string) string {
+	label := fmt.Sprintf("%-12s", kind)
+	switch kind {
+	case history.ChangeNew:
+		return Green("+ " + label)
+	case history.ChangeRemoved:
+		return Red("- " + label)
+	case history.ChangeMoved:
+		return Cyan("> " + label)
+	default:
+		return Yellow("~ " + label)
+	}
+}
+
+func changeText(c history.Change) string {
+	switch c.Kind {
+	case history.ChangeStatus:
+		return StatusColor(c.From) + " → " + StatusColor(c.To)
+	case history.ChangeMoved:
+		return Gray(c.From) + " → " + c.To
+	case history.ChangeNew, history.ChangeRemoved:
+		if c.Detail != "" {
+			return Gray(c.Path) + "  " + c.Detail
+		}
+		return Gray(c.Path)
+	default:
+		return c.Detail
+	}
+}
+
+func formatScanTime(at string) string {
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return at
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
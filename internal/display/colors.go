@@ -1,6 +1,11 @@
 package display
 
-import "github.com/fatih/color"
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/peeomid/prj/internal/project"
+)
 
 var (
 	Green  = color.New(color.FgGreen).SprintFunc()
@@ -12,6 +17,29 @@ var (
 	Cyan   = color.New(color.FgCyan).SprintFunc()
 )
 
+var namedColors = map[string]color.Attribute{
+	"green":   color.FgGreen,
+	"blue":    color.FgBlue,
+	"yellow":  color.FgYellow,
+	"gray":    color.FgHiBlack,
+	"grey":    color.FgHiBlack,
+	"red":     color.FgRed,
+	"cyan":    color.FgCyan,
+	"magenta": color.FgMagenta,
+	"white":   color.FgWhite,
+}
+
+// customStatusColors holds colors for user-defined statuses.
+var customStatusColors = map[string]func(a ...interface{}) string{}
+
+// SetStatusColor registers a color (by name, e.g. "magenta") for a custom
+// status. Unknown color names are ignored.
+func SetStatusColor(status, name string) {
+	if attr, ok := namedColors[strings.ToLower(name)]; ok {
+		customStatusColors[status] = color.New(attr).SprintFunc()
+	}
+}
+
 // StatusColor returns the status string with appropriate color.
 func StatusColor(status string) string {
 	switch status {
@@ -21,9 +49,38 @@ func StatusColor(status string) string {
 		return Blue(status)
 	case "recent":
 		return Yellow(status)
-	case "paused":
+	case "paused", "archived", "abandoned":
 		return Gray(status)
+	case "idea":
+		return Cyan(status)
 	default:
+		if c, ok := customStatusColors[status]; ok {
+			return c(status)
+		}
 		return status
 	}
 }
+
+// StatusLabel is a project's colored effective status, decorated with a
+// marker for the focus and maintained marks.
+func StatusLabel(p *project.Project) string {
+	s := StatusColor(p.EffectiveStatus())
+	switch p.Mark {
+	case project.MarkFocus:
+		s += " " + Yellow("★")
+	case project.MarkMaintained:
+		s += " " + Gray("⚙")
+	}
+	return s
+}
+
+// StatusText is StatusLabel without colors.
+func StatusText(p *project.Project) string {
+	switch p.Mark {
+	case project.MarkFocus:
+		return p.EffectiveStatus() + " ★"
+	case project.MarkMaintained:
+		return p.EffectiveStatus() + " ⚙"
+	}
+	return p.EffectiveStatus()
+}
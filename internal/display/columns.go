@@ -0,0 +1,185 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peeomid/prj/internal/expr"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Column is one column of "prj list" output.
+type Column struct {
+	Name   string
+	Header string
+	// Cell renders the value for the table, Plain for csv, tsv and
+	// markdown.
+	Cell  func(p *project.Project) string
+	Plain func(p *project.Project) string
+	// Paint colors a table cell after it has been fitted. Optional.
+	Paint func(p *project.Project, s string) string
+	// Flex columns give up width when the table is wider than the
+	// terminal; ElideLeft keeps the end of the value (paths) instead of
+	// the start.
+	Flex      bool
+	ElideLeft bool
+}
+
+// DefaultTableColumns are the columns of the "prj list" table.
+var DefaultTableColumns = []string{"name", "type", "status", "tech", "last_commit", "commits"}
+
+// DefaultColumns are the fields written per project by the csv, tsv and
+// markdown formats.
+var DefaultColumns = []string{"name", "type", "status", "tech", "last_commit", "commits", "path"}
+
+// builtinColumns render fields the table shows in a friendlier way than
+// FieldValue does. Every other project.Fields name is a column too.
+var builtinColumns = map[string]Column{
+	"name": {Header: "Name", Cell: func(p *project.Project) string { return p.Name }, Flex: true},
+	"type": {Header: "Type", Cell: func(p *project.Project) string { return p.InferredType }},
+	"status": {
+		Header: "Status",
+		Cell: func(p *project.Project) string {
+			if p.Missing != "" {
+				return "missing"
+			}
+			return StatusText(p)
+		},
+		Plain: func(p *project.Project) string { return p.EffectiveStatus() },
+		Paint: func(p *project.Project, s string) string {
+			if p.Missing != "" {
+				return Red(s)
+			}
+			return StatusLabel(p)
+		},
+	},
+	"tech": {Header: "Tech", Cell: func(p *project.Project) string { return strings.Join(p.TechStack, ",") }, Flex: true},
+	"last_commit": {
+		Header: "Last Commit",
+		Cell:   func(p *project.Project) string { return FormatAge(p.LastCommitDate) },
+		Plain:  func(p *project.Project) string { return p.LastCommitDate },
+	},
+	"commits": {Header: "Commits(8m)", Cell: func(p *project.Project) string { return strconv.Itoa(p.CommitCount8M) }},
+	"todo": {
+		Header: "TODO",
+		Cell:   func(p *project.Project) string { return strconv.Itoa(p.TodoOpen) },
+	},
+	"path": {
+		Header:    "Path",
+		Cell:      func(p *project.Project) string { return shortenHome(p.Path) },
+		Plain:     func(p *project.Project) string { return p.Path },
+		Flex:      true,
+		ElideLeft: true,
+	},
+	"tags": {
+		Header: "Tags",
+		Cell:   func(p *project.Project) string { return strings.Join(p.Tags, ",") },
+		Paint:  func(p *project.Project, s string) string { return Cyan(s) },
+		Flex:   true,
+	},
+}
+
+// LookupColumn returns the column for a field name: one of the built-in
+// columns or any project.Fields name.
+func LookupColumn(name string) (Column, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if c, ok := builtinColumns[name]; ok {
+		c.Name = name
+		if c.Plain == nil {
+			c.Plain = c.Cell
+		}
+		return c, nil
+	}
+	kind, ok := project.Fields[name]
+	if !ok {
+		return Column{}, fmt.Errorf("unknown column %q (known: %s)", name, strings.Join(ColumnNames(), ", "))
+	}
+	c := Column{
+		Name:   name,
+		Header: headerFor(name),
+		Plain:  func(p *project.Project) string { return FieldValue(p, name) },
+		Cell:   func(p *project.Project) string { return FieldValue(p, name) },
+	}
+	switch kind {
+	case expr.KindDate:
+		c.Cell = func(p *project.Project) string {
+			t, _ := p.Lookup(name).(time.Time)
+			if t.IsZero() {
+				return "never"
+			}
+			return FormatAge(t.Format(time.RFC3339))
+		}
+	case expr.KindString, expr.KindList:
+		c.Flex = true
+		if name == "folder" {
+			c.ElideLeft = true
+			c.Cell = func(p *project.Project) string { return shortenHome(FieldValue(p, name)) }
+		}
+	}
+	return c, nil
+}
+
+// Columns resolves column names, rejecting unknown ones.
+func Columns(names []string) ([]Column, error) {
+	cols := make([]Column, 0, len(names))
+	for _, n := range names {
+		if strings.TrimSpace(n) == "" {
+			continue
+		}
+		c, err := LookupColumn(n)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, c)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return cols, nil
+}
+
+// ColumnNames lists every column name, sorted.
+func ColumnNames() []string {
+	seen := map[string]bool{}
+	for n := range builtinColumns {
+		seen[n] = true
+	}
+	for n := range project.Fields {
+		seen[n] = true
+	}
+	names := make([]string, 0, len(seen))
+	for n := range seen {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// headerFor turns days_since_commit into "Days Since Commit".
+func headerFor(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
@@ -2,6 +2,8 @@ package display
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/peeomid/prj/internal/project"
@@ -9,77 +11,115 @@ import (
 
 // PrintDetail renders a full detail view for a single project.
 func PrintDetail(p *project.Project) {
-	fmt.Println()
-	fmt.Printf("  %s  %s\n", Bold(p.Name), StatusColor(p.Status))
-	fmt.Printf("  %s\n", Gray(p.Path))
-	fmt.Println()
+	WriteDetail(os.Stdout, p)
+}
+
+// WriteDetail writes PrintDetail's view to w.
+func WriteDetail(w io.Writer, p *project.Project) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s  %s\n", Bold(p.Name), StatusLabel(p))
+	fmt.Fprintf(w, "  %s\n", Gray(p.Path))
+	if p.Missing != "" {
+		fmt.Fprintf(w, "  %s %s (run: prj prune)\n", Red("missing:"), p.Missing)
+	}
+	fmt.Fprintln(w)
 
 	if p.Description != "" {
-		fmt.Printf("  %s\n\n", p.Description)
+		fmt.Fprintf(w, "  %s\n\n", p.Description)
 	}
 
-	section("Type", p.InferredType)
-	section("Tech", strings.Join(p.TechStack, ", "))
-	section("Remote", p.GitRemote)
+	if p.Mark != "" {
+		section(w, "Mark", fmt.Sprintf("%s %s", p.Mark, Gray("(since "+FormatAge(p.MarkedAt)+", inferred: "+p.Status+")")))
+	}
+	if len(p.Tags) > 0 {
+		section(w, "Tags", Cyan(strings.Join(p.Tags, ", ")))
+	}
+	section(w, "Type", p.InferredType)
+	section(w, "Tech", strings.Join(p.TechStack, ", "))
+	section(w, "Remote", p.GitRemote)
 
 	if p.IsFork {
-		section("Fork", "yes")
+		section(w, "Fork", "yes")
 	}
 
-	fmt.Println()
-	fmt.Printf("  %s\n", Bold("Git"))
-	section("  Last commit", fmt.Sprintf("%s — %s (%s)", formatAge(p.LastCommitDate), p.LastCommitMessage, p.LastCommitAuthor))
-	section("  Commits (8m)", fmt.Sprintf("%d", p.CommitCount8M))
-	section("  Contributors", strings.Join(p.Contributors, ", "))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %s\n", Bold("Git"))
+	section(w, "  Last commit", fmt.Sprintf("%s — %s (%s)", FormatAge(p.LastCommitDate), p.LastCommitMessage, p.LastCommitAuthor))
+	section(w, "  Commits (8m)", fmt.Sprintf("%d", p.CommitCount8M))
+	section(w, "  Contributors", strings.Join(p.Contributors, ", "))
+	if p.Dirty || p.Unpushed > 0 {
+		var state []string
+		if p.Dirty {
+			state = append(state, Yellow("uncommitted changes"))
+		}
+		if p.Unpushed > 0 {
+			state = append(state, Red(fmt.Sprintf("%d unpushed", p.Unpushed)))
+		}
+		section(w, "  Working tree", strings.Join(state, ", "))
+	}
 
 	if len(p.RecentCommits) > 0 {
-		fmt.Printf("\n  %s\n", Bold("Recent Commits"))
+		fmt.Fprintf(w, "\n  %s\n", Bold("Recent Commits"))
 		for _, c := range p.RecentCommits {
-			fmt.Printf("    %s %s %s\n", Gray(c.Date[:10]), Cyan(c.Hash[:7]), c.Message)
+			fmt.Fprintf(w, "    %s %s %s\n", Gray(c.Date[:10]), Cyan(c.Hash[:7]), c.Message)
+		}
+	}
+
+	if p.Note != "" {
+		fmt.Fprintf(w, "\n  %s  %s\n", Bold("Note"), Gray("("+FormatAge(p.NoteUpdatedAt)+")"))
+		for _, line := range strings.Split(p.Note, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
 		}
 	}
 
 	if p.TodoOpen > 0 || p.TodoClosed > 0 {
-		fmt.Printf("\n  %s  open:%s  closed:%s\n", Bold("TODOs"), Green(fmt.Sprintf("%d", p.TodoOpen)), Gray(fmt.Sprintf("%d", p.TodoClosed)))
+		fmt.Fprintf(w, "\n  %s  open:%s  closed:%s\n", Bold("TODOs"), Green(fmt.Sprintf("%d", p.TodoOpen)), Gray(fmt.Sprintf("%d", p.TodoClosed)))
 	}
 
 	if len(p.Deployment) > 0 {
-		fmt.Printf("\n  %s  %s\n", Bold("Deployment"), strings.Join(p.Deployment, ", "))
+		fmt.Fprintf(w, "\n  %s  %s\n", Bold("Deployment"), strings.Join(p.Deployment, ", "))
 	}
 
 	if len(p.ReferenceFiles.Root) > 0 {
-		fmt.Printf("\n  %s\n", Bold("Reference Files"))
-		printRefList("Root", p.ReferenceFiles.Root)
-		printRefList("AI", p.ReferenceFiles.AI)
-		printRefList("Cursor", p.ReferenceFiles.Cursor)
-		printRefList("Docs", p.ReferenceFiles.Docs)
-		printRefList("Tasks", p.ReferenceFiles.Tasks)
+		fmt.Fprintf(w, "\n  %s\n", Bold("Reference Files"))
+		printRefList(w, "Root", p.ReferenceFiles.Root)
+		printRefList(w, "AI", p.ReferenceFiles.AI)
+		printRefList(w, "Cursor", p.ReferenceFiles.Cursor)
+		printRefList(w, "Docs", p.ReferenceFiles.Docs)
+		printRefList(w, "Tasks", p.ReferenceFiles.Tasks)
+	}
+
+	if len(p.Moves) > 0 {
+		fmt.Fprintf(w, "\n  %s\n", Bold("Moved"))
+		for _, m := range p.Moves {
+			fmt.Fprintf(w, "    %s %s → %s\n", Gray(m.At[:10]), m.From, m.To)
+		}
 	}
 
 	if len(p.NestedRepos) > 0 {
-		fmt.Printf("\n  %s  %s\n", Bold("Nested Repos"), strings.Join(p.NestedRepos, ", "))
+		fmt.Fprintf(w, "\n  %s  %s\n", Bold("Nested Repos"), strings.Join(p.NestedRepos, ", "))
 	}
 
 	if len(p.Errors) > 0 {
-		fmt.Printf("\n  %s\n", Red("Errors"))
+		fmt.Fprintf(w, "\n  %s\n", Red("Errors"))
 		for _, e := range p.Errors {
-			fmt.Printf("    %s\n", Red(e))
+			fmt.Fprintf(w, "    %s\n", Red(e))
 		}
 	}
 
-	fmt.Printf("\n  %s %s\n\n", Gray("Scanned:"), Gray(p.ScannedAt))
+	fmt.Fprintf(w, "\n  %s %s\n\n", Gray("Scanned:"), Gray(p.ScannedAt))
 }
 
-func section(label, value string) {
+func section(w io.Writer, label, value string) {
 	if value == "" {
 		return
 	}
-	fmt.Printf("  %-16s %s\n", Bold(label), value)
+	fmt.Fprintf(w, "  %-16s %s\n", Bold(label), value)
 }
 
-func printRefList(label string, files []string) {
+func printRefList(w io.Writer, label string, files []string) {
 	if len(files) == 0 {
 		return
 	}
-	fmt.Printf("    %-10s %s\n", label+":", strings.Join(files, ", "))
+	fmt.Fprintf(w, "    %-10s %s\n", label+":", strings.Join(files, ", "))
 }
@@ -0,0 +1,257 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/peeomid/prj/internal/expr"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// GroupKeys are the values "prj list --group-by" accepts.
+var GroupKeys = []string{"type", "status", "folder", "tech", "remote-host", "tag"}
+
+// Group is a set of projects sharing a --group-by value.
+type Group struct {
+	Name     string             `json:"group"`
+	Count    int                `json:"count"`
+	Sums     map[string]float64 `json:"sums,omitempty"`
+	Projects []*project.Project `json:"projects"`
+}
+
+// groupKeys returns the groups p belongs to. A project is in one group for
+// every key except tag, where it is in one per tag. "tech" is the first
+// (primary) entry of the tech stack.
+func groupKeys(p *project.Project, by string) []string {
+	switch by {
+	case "type":
+		return []string{p.InferredType}
+	case "status":
+		return []string{p.EffectiveStatus()}
+	case "folder":
+		return []string{shortenHome(filepath.Dir(p.Path))}
+	case "tech":
+		if len(p.TechStack) == 0 {
+			return []string{"(no tech)"}
+		}
+		return []string{p.TechStack[0]}
+	case "remote-host":
+		if h := project.RemoteHost(p.GitRemote); h != "" {
+			return []string{h}
+		}
+		return []string{"(no remote)"}
+	case "tag":
+		if len(p.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		keys := make([]string, len(p.Tags))
+		for i, t := range p.Tags {
+			keys[i] = strings.ToLower(t)
+		}
+		return keys
+	}
+	return nil
+}
+
+// GroupProjects splits projects (already sorted) by key, keeping their
+// order within each group, and totals the sum fields per group. Groups are
+// sorted by name, with the "(none)"-style groups last.
+func GroupProjects(projects []*project.Project, by string, sums []string) ([]*Group, error) {
+	if err := checkGroupKey(by); err != nil {
+		return nil, err
+	}
+	byName := map[string]*Group{}
+	var groups []*Group
+	for _, p := range projects {
+		for _, k := range groupKeys(p, by) {
+			g, ok := byName[k]
+			if !ok {
+				g = &Group{Name: k}
+				byName[k] = g
+				groups = append(groups, g)
+			}
+			g.Projects = append(g.Projects, p)
+			g.Count++
+		}
+	}
+	for _, g := range groups {
+		if len(sums) > 0 {
+			g.Sums = map[string]float64{}
+		}
+		for _, f := range sums {
+			for _, p := range g.Projects {
+				n, _ := p.Lookup(f).(float64)
+				g.Sums[f] += n
+			}
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		ni, nj := strings.HasPrefix(groups[i].Name, "("), strings.HasPrefix(groups[j].Name, "(")
+		if ni != nj {
+			return nj
+		}
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups, nil
+}
+
+func checkGroupKey(by string) error {
+	for _, k := range GroupKeys {
+		if k == by {
+			return nil
+		}
+	}
+	return fmt.Errorf("can't group by %q (want %s)", by, strings.Join(GroupKeys, "|"))
+}
+
+// SumFields resolves --sum names to numeric fields; "todo" means todo_open.
+func SumFields(names []string) ([]string, error) {
+	var fields []string
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		if n == "todo" {
+			n = "todo_open"
+		}
+		if n == "" {
+			continue
+		}
+		if project.Fields[n] != expr.KindNumber {
+			return nil, fmt.Errorf("can't sum %q (numeric fields: todo, %s)", n, strings.Join(NumericFields(), ", "))
+		}
+		fields = append(fields, n)
+	}
+	return fields, nil
+}
+
+// NumericFields lists the project.Fields names --sum accepts, sorted.
+func NumericFields() []string {
+	var numeric []string
+	for f, k := range project.Fields {
+		if k == expr.KindNumber {
+			numeric = append(numeric, f)
+		}
+	}
+	sort.Strings(numeric)
+	return numeric
+}
+
+// sumsText renders "45 commits · 12 todo_open" for a group header.
+func sumsText(sums map[string]float64, fields []string) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		label := sumLabel(f)
+		if sums[f] == 1 {
+			label = strings.TrimSuffix(label, "s")
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", strconv.FormatFloat(sums[f], 'f', -1, 64), label))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func sumLabel(field string) string {
+	switch field {
+	case "commits", "commit_count_8m":
+		return "commits"
+	case "todo_open":
+		return "open TODOs"
+	case "todo_closed":
+		return "closed TODOs"
+	}
+	return strings.ReplaceAll(field, "_", " ")
+}
+
+// PrintGroups renders each group as a headed table. Columns line up across
+// groups and fit the terminal like PrintTable.
+func PrintGroups(groups []*Group, cols []Column, sums []string) {
+	if len(groups) == 0 {
+		fmt.Println("No projects found.")
+		return
+	}
+	if cols == nil {
+		cols, _ = Columns(DefaultTableColumns)
+	}
+
+	var all []*project.Project
+	for _, g := range groups {
+		all = append(all, g.Projects...)
+	}
+	widths := fitWidths(cols, cellRows(all, cols), TerminalWidth())
+
+	seen := map[*project.Project]bool{}
+	total := map[string]float64{}
+	for _, g := range groups {
+		header := fmt.Sprintf("%s  %s", Bold(g.Name), Gray(plural(g.Count, "project")))
+		if len(sums) > 0 {
+			header += Gray(" · " + sumsText(g.Sums, sums))
+		}
+		fmt.Printf("\n%s\n", header)
+		printTable(g.Projects, cols, widths)
+		for _, p := range g.Projects {
+			if !seen[p] {
+				seen[p] = true
+				for _, f := range sums {
+					n, _ := p.Lookup(f).(float64)
+					total[f] += n
+				}
+			}
+		}
+	}
+
+	footer := fmt.Sprintf("%s in %s", plural(len(seen), "project"), plural(len(groups), "group"))
+	if len(sums) > 0 {
+		footer += " · " + sumsText(total, sums)
+	}
+	fmt.Printf("\n%s\n", Bold(footer))
+}
+
+// WriteGroups writes groups in a non-table format: group objects for json,
+// jsonl and yaml, or rows with a leading group column otherwise.
+func WriteGroups(w io.Writer, format string, groups []*Group, cols []Column) error {
+	if groups == nil {
+		groups = []*Group{} // [] rather than null
+	}
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, groups)
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, g := range groups {
+			if err := enc.Encode(g); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		return writeYAML(w, groups)
+	}
+	if cols == nil {
+		cols, _ = Columns(DefaultColumns)
+	}
+	header := []string{"group"}
+	for _, c := range cols {
+		header = append(header, c.Name)
+	}
+	var rows [][]string
+	for _, g := range groups {
+		for _, p := range g.Projects {
+			row := []string{g.Name}
+			for _, c := range cols {
+				row = append(row, c.Plain(p))
+			}
+			rows = append(rows, row)
+		}
+	}
+	return writeRows(w, format, header, rows)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
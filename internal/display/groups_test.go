@@ -0,0 +1,81 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+func groupFixture() []*project.Project {
+	return []*project.Project{
+		{Name: "api", Path: "/dev/work/api", Status: "active", TechStack: []string{"go", "docker"}, CommitCount8M: 40, TodoOpen: 3, Tags: []string{"Acme", "backend"}, GitRemote: "git@github.com:u/api.git"},
+		{Name: "web", Path: "/dev/work/web", Status: "wip", TechStack: []string{"react"}, CommitCount8M: 12, TodoOpen: 1, Tags: []string{"acme"}, GitRemote: "https://gitlab.com/u/web"},
+		{Name: "cli", Path: "/dev/cli", Status: "active", TechStack: []string{"go"}, CommitCount8M: 5},
+		{Name: "notes", Path: "/dev/notes", Status: "paused", Mark: "archived"},
+	}
+}
+
+// groupsText renders groups as "name count [sums]: project names".
+func groupsText(groups []*Group, sums []string) string {
+	var lines []string
+	for _, g := range groups {
+		var names []string
+		for _, p := range g.Projects {
+			names = append(names, p.Name)
+		}
+		line := fmt.Sprintf("%s %d", g.Name, g.Count)
+		for _, f := range sums {
+			line += fmt.Sprintf(" %s=%g", f, g.Sums[f])
+		}
+		lines = append(lines, line+": "+strings.Join(names, ","))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestGroupProjects(t *testing.T) {
+	tests := []struct {
+		by   string
+		sums []string
+		want string
+	}{
+		// Groups by name; members keep the input order.
+		{"status", nil, "active 2: api,cli\narchived 1: notes\nwip 1: web"},
+		// The primary tech only; "(...)" groups come last.
+		{"tech", []string{"commits", "todo_open"},
+			"go 2 commits=45 todo_open=3: api,cli\nreact 1 commits=12 todo_open=1: web\n(no tech) 1 commits=0 todo_open=0: notes"},
+		// A project is in every one of its tags, case-folded.
+		{"tag", []string{"commits"},
+			"acme 2 commits=52: api,web\nbackend 1 commits=40: api\n(untagged) 2 commits=5: cli,notes"},
+		{"folder", nil, "/dev 2: cli,notes\n/dev/work 2: api,web"},
+		{"remote-host", nil, "github.com 1: api\ngitlab.com 1: web\n(no remote) 2: cli,notes"},
+	}
+	for _, tt := range tests {
+		groups, err := GroupProjects(groupFixture(), tt.by, tt.sums)
+		if err != nil {
+			t.Errorf("GroupProjects(%s): %v", tt.by, err)
+			continue
+		}
+		if got := groupsText(groups, tt.sums); got != tt.want {
+			t.Errorf("GroupProjects(%s):\n%s\nwant:\n%s", tt.by, got, tt.want)
+		}
+	}
+
+	if _, err := GroupProjects(groupFixture(), "color", nil); err == nil {
+		t.Error("GroupProjects(color) succeeded")
+	}
+	if groups, err := GroupProjects(nil, "type", nil); err != nil || len(groups) != 0 {
+		t.Errorf("GroupProjects(nil) = %v, %v", groups, err)
+	}
+}
+
+func TestSumFields(t *testing.T) {
+	got, err := SumFields([]string{"todo", " Commits ", ""})
+	if err != nil || strings.Join(got, ",") != "todo_open,commits" {
+		t.Errorf("SumFields = %v, %v; want todo_open,commits", got, err)
+	}
+	if _, err := SumFields([]string{"name"}); err == nil {
+		t.Error("SumFields accepted a string field")
+	}
+}
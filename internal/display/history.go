@@ -0,0 +1,125 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/peeomid/prj/internal/history"
+	"github.com/peeomid/prj/internal/project"
+	"github.com/rodaine/table"
+)
+
+// PrintHistory renders a project's snapshots over time. Consecutive scans
+// where nothing changed are collapsed into one row.
+func PrintHistory(p *project.Project, points []history.Point) {
+	fmt.Printf("\n  %s  %s\n", Bold(p.Name), Gray(p.Path))
+	if len(points) == 0 {
+		fmt.Println("\n  No history yet. It is recorded on every: prj scan")
+		fmt.Println()
+		return
+	}
+	fmt.Println()
+
+	tbl := table.New("Scanned", "Status", "Commits(8m)", "TODO open", "TODO closed", "Tech")
+	tbl.WithWriter(os.Stdout)
+
+	var prev *history.Point
+	rows := 0
+	for i := range points {
+		pt := &points[i]
+		if prev != nil && sameSnapshot(prev.Snapshot, pt.Snapshot) {
+			continue
+		}
+		tech := strings.Join(pt.TechStack, ",")
+		if prev != nil {
+			tech = techDelta(prev.TechStack, pt.TechStack)
+		}
+		tbl.AddRow(
+			pt.At[:10],
+			StatusColor(pt.Status),
+			pt.CommitCount8M,
+			pt.TodoOpen,
+			pt.TodoClosed,
+			tech,
+		)
+		prev = pt
+		rows++
+	}
+
+	tbl.Print()
+	fmt.Printf("\n%s scans, %s changes\n", Bold(fmt.Sprintf("%d", len(points))), Bold(fmt.Sprintf("%d", rows)))
+}
+
+// PrintTrends renders fleet-wide totals per period.
+func PrintTrends(trends []history.Trend) {
+	if len(trends) == 0 {
+		fmt.Println("No history yet. It is recorded on every: prj scan")
+		return
+	}
+
+	tbl := table.New("Period", "Projects", "Active", "WIP", "Recent", "Paused", "Commits(8m)", "TODO open", "Top tech")
+	tbl.WithWriter(os.Stdout)
+
+	for _, t := range trends {
+		tbl.AddRow(
+			t.Period,
+			t.Projects,
+			Green(fmt.Sprintf("%d", t.Status["active"])),
+			Blue(fmt.Sprintf("%d", t.Status["wip"])),
+			Yellow(fmt.Sprintf("%d", t.Status["recent"])),
+			Gray(fmt.Sprintf("%d", t.Status["paused"])),
+			t.Commits8M,
+			t.TodoOpen,
+			topKeys(t.Tech, 3),
+		)
+	}
+
+	tbl.Print()
+	fmt.Println()
+}
+
+func sameSnapshot(a, b history.Snapshot) bool {
+	return a.Status == b.Status &&
+		a.CommitCount8M == b.CommitCount8M &&
+		a.TodoOpen == b.TodoOpen &&
+		a.TodoClosed == b.TodoClosed &&
+		strings.Join(a.TechStack, ",") == strings.Join(b.TechStack, ",")
+}
+
+// techDelta describes how the tech stack changed, e.g. "+react -vue".
+func techDelta(before, after []string) string {
+	had := map[string]bool{}
+	for _, t := range before {
+		had[t] = true
+	}
+	has := map[string]bool{}
+	var parts []string
+	for _, t := range after {
+		has[t] = true
+		if !had[t] {
+			parts = append(parts, Green("+"+t))
+		}
+	}
+	for _, t := range before {
+		if !has[t] {
+			parts = append(parts, Red("-"+t))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// topKeys returns the n most common keys as "go:12,node:9".
+func topKeys(m map[string]int, n int) string {
+	keys := sortedKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool { return m[keys[i]] > m[keys[j]] })
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s:%d", k, m[k])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,243 @@
+package display
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/peeomid/prj/internal/project"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for --output. Table is the colored human view; the rest
+// are for scripts and never colored.
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatJSONL    = "jsonl"
+	FormatCSV      = "csv"
+	FormatTSV      = "tsv"
+	FormatYAML     = "yaml"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists every output format.
+var Formats = []string{FormatTable, FormatJSON, FormatJSONL, FormatCSV, FormatTSV, FormatYAML, FormatMarkdown}
+
+// CheckFormat returns an error naming the valid formats if f isn't one.
+func CheckFormat(f string) error {
+	for _, v := range Formats {
+		if v == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (want %s)", f, strings.Join(Formats, "|"))
+}
+
+// DisableColor turns off colors for everything printed from now on.
+func DisableColor() {
+	color.NoColor = true
+}
+
+// EnableColor turns colors on even when stdout isn't a terminal, as in a
+// shell prompt's command substitution. NO_COLOR still wins.
+func EnableColor() {
+	if os.Getenv("NO_COLOR") == "" {
+		color.NoColor = false
+	}
+}
+
+// FieldValue renders a field (a project.Fields name) as plain text: lists
+// comma-joined, dates as RFC 3339, numbers without decimals.
+func FieldValue(p *project.Project, field string) string {
+	switch v := p.Lookup(field).(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		return strings.Join(v, ",")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// WriteProjects writes projects in a non-table format. json and yaml get
+// the full records; csv, tsv and markdown get one row per project with the
+// given columns (nil for DefaultColumns).
+func WriteProjects(w io.Writer, format string, projects []*project.Project, cols []Column) error {
+	if projects == nil {
+		projects = []*project.Project{} // [] rather than null
+	}
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, projects)
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, p := range projects {
+			if err := enc.Encode(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		return writeYAML(w, projects)
+	}
+	if cols == nil {
+		cols, _ = Columns(DefaultColumns)
+	}
+	header := make([]string, len(cols))
+	for j, c := range cols {
+		header[j] = c.Name
+	}
+	rows := make([][]string, len(projects))
+	for i, p := range projects {
+		rows[i] = make([]string, len(cols))
+		for j, c := range cols {
+			rows[i][j] = c.Plain(p)
+		}
+	}
+	return writeRows(w, format, header, rows)
+}
+
+// WriteProject writes a single project: an object for json and yaml, a
+// one-row table otherwise.
+func WriteProject(w io.Writer, format string, p *project.Project) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, p)
+	case FormatYAML:
+		return writeYAML(w, p)
+	}
+	return WriteProjects(w, format, []*project.Project{p}, nil)
+}
+
+// WriteSummary writes the status report: the Summary object for json,
+// jsonl and yaml, or section/key/value rows for the tabular formats.
+func WriteSummary(w io.Writer, format string, s *Summary) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, s)
+	case FormatJSONL:
+		return json.NewEncoder(w).Encode(s)
+	case FormatYAML:
+		return writeYAML(w, s)
+	}
+	return writeRows(w, format, []string{"section", "key", "value"}, s.Rows())
+}
+
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeYAML goes through JSON so keys and their order match the json
+// format.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow style JSON parses into.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+func writeRows(w io.Writer, format string, header []string, rows [][]string) error {
+	switch format {
+	case FormatCSV, FormatTSV:
+		cw := csv.NewWriter(w)
+		if format == FormatTSV {
+			cw.Comma = '\t'
+		}
+		cw.Write(header)
+		cw.WriteAll(rows)
+		return cw.Error()
+	case FormatMarkdown:
+		var b bytes.Buffer
+		mdRow(&b, header)
+		sep := make([]string, len(header))
+		for i := range sep {
+			sep[i] = "---"
+		}
+		mdRow(&b, sep)
+		for _, r := range rows {
+			mdRow(&b, r)
+		}
+		_, err := w.Write(b.Bytes())
+		return err
+	}
+	return fmt.Errorf("format %q has no rows", format)
+}
+
+func mdRow(b *bytes.Buffer, cells []string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		c = strings.ReplaceAll(c, "|", `\|`)
+		c = strings.ReplaceAll(c, "\n", " ")
+		b.WriteString(" " + c + " |")
+	}
+	b.WriteString("\n")
+}
+
+// ParseTemplate compiles a --template. Besides the text/template builtins
+// it has join (join .TechStack ", "), json, age (age .LastCommitDate) and
+// field (field . "days_since_commit", any project.Fields name).
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(template.FuncMap{
+		"join": func(items []string, sep string) string { return strings.Join(items, sep) },
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"age":   FormatAge,
+		"field": FieldValue,
+	}).Parse(text)
+}
+
+// ExecTemplate runs tmpl on data, ending the output with a newline.
+func ExecTemplate(w io.Writer, tmpl *template.Template, data any) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,229 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+func outputFixture() []*project.Project {
+	return []*project.Project{
+		{Name: "api", Path: "/dev/api", Status: "active", InferredType: "go-app", TechStack: []string{"go"}, LastCommitDate: "2026-01-02T03:04:05Z", CommitCount8M: 12, Tags: []string{"acme"}},
+		{Name: "web, app", Path: "/dev/web app", Status: "paused", Mark: "archived", InferredType: "react-app", TechStack: []string{"node", "react"}, Note: "line one\nline \"two\""},
+	}
+}
+
+func TestWriteProjects(t *testing.T) {
+	cols, err := Columns([]string{"name", "status", "tech", "commits", "note"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		format string
+		cols   []Column
+		want   string
+	}{
+		{FormatCSV, nil, `name,type,status,tech,last_commit,commits,path
+api,go-app,active,go,2026-01-02T03:04:05Z,12,/dev/api
+"web, app",react-app,archived,"node,react",,0,/dev/web app
+`},
+		{FormatCSV, cols, `name,status,tech,commits,note
+api,active,go,12,
+"web, app",archived,"node,react",0,"line one
+line ""two"""
+`},
+		{FormatTSV, cols, "name\tstatus\ttech\tcommits\tnote\n" +
+			"api\tactive\tgo\t12\t\n" +
+			"web, app\tarchived\tnode,react\t0\t\"line one\nline \"\"two\"\"\"\n"},
+		{FormatMarkdown, cols, `| name | status | tech | commits | note |
+| --- | --- | --- | --- | --- |
+| api | active | go | 12 |  |
+| web, app | archived | node,react | 0 | line one line "two" |
+`},
+		{FormatJSON, nil, `[
+  {
+    "name": "api",
+    "path": "/dev/api",
+    "description": "",
+    "tech_stack": [
+      "go"
+    ],
+    "inferred_type": "go-app",
+    "status": "active",
+    "is_fork": false,
+    "git_remote": "",
+    "last_commit_date": "2026-01-02T03:04:05Z",
+    "last_commit_message": "",
+    "last_commit_author": "",
+    "recent_commits": null,
+    "commit_count_8m": 12,
+    "contributors": null,
+    "reference_files": {
+      "root": null,
+      "ai": null,
+      "cursor": null,
+      "docs": null,
+      "tasks": null
+    },
+    "todo_open": 0,
+    "todo_closed": 0,
+    "deployment": null,
+    "plans_count": 0,
+    "ai_docs_count": 0,
+    "scanned_at": "",
+    "tags": [
+      "acme"
+    ]
+  },
+  {
+    "name": "web, app",
+    "path": "/dev/web app",
+    "description": "",
+    "tech_stack": [
+      "node",
+      "react"
+    ],
+    "inferred_type": "react-app",
+    "status": "paused",
+    "is_fork": false,
+    "git_remote": "",
+    "last_commit_date": "",
+    "last_commit_message": "",
+    "last_commit_author": "",
+    "recent_commits": null,
+    "commit_count_8m": 0,
+    "contributors": null,
+    "reference_files": {
+      "root": null,
+      "ai": null,
+      "cursor": null,
+      "docs": null,
+      "tasks": null
+    },
+    "todo_open": 0,
+    "todo_closed": 0,
+    "deployment": null,
+    "plans_count": 0,
+    "ai_docs_count": 0,
+    "scanned_at": "",
+    "mark": "archived",
+    "note": "line one\nline \"two\""
+  }
+]
+`},
+		{FormatJSONL, nil, `{"name":"api","path":"/dev/api","description":"","tech_stack":["go"],"inferred_type":"go-app","status":"active","is_fork":false,"git_remote":"","last_commit_date":"2026-01-02T03:04:05Z","last_commit_message":"","last_commit_author":"","recent_commits":null,"commit_count_8m":12,"contributors":null,"reference_files":{"root":null,"ai":null,"cursor":null,"docs":null,"tasks":null},"todo_open":0,"todo_closed":0,"deployment":null,"plans_count":0,"ai_docs_count":0,"scanned_at":"","tags":["acme"]}
+{"name":"web, app","path":"/dev/web app","description":"","tech_stack":["node","react"],"inferred_type":"react-app","status":"paused","is_fork":false,"git_remote":"","last_commit_date":"","last_commit_message":"","last_commit_author":"","recent_commits":null,"commit_count_8m":0,"contributors":null,"reference_files":{"root":null,"ai":null,"cursor":null,"docs":null,"tasks":null},"todo_open":0,"todo_closed":0,"deployment":null,"plans_count":0,"ai_docs_count":0,"scanned_at":"","mark":"archived","note":"line one\nline \"two\""}
+`},
+		{FormatYAML, nil, `- name: api
+  path: /dev/api
+  description: ""
+  tech_stack:
+    - go
+  inferred_type: go-app
+  status: active
+  is_fork: false
+  git_remote: ""
+  last_commit_date: "2026-01-02T03:04:05Z"
+  last_commit_message: ""
+  last_commit_author: ""
+  recent_commits: null
+  commit_count_8m: 12
+  contributors: null
+  reference_files:
+    root: null
+    ai: null
+    cursor: null
+    docs: null
+    tasks: null
+  todo_open: 0
+  todo_closed: 0
+  deployment: null
+  plans_count: 0
+  ai_docs_count: 0
+  scanned_at: ""
+  tags:
+    - acme
+- name: web, app
+  path: /dev/web app
+  description: ""
+  tech_stack:
+    - node
+    - react
+  inferred_type: react-app
+  status: paused
+  is_fork: false
+  git_remote: ""
+  last_commit_date: ""
+  last_commit_message: ""
+  last_commit_author: ""
+  recent_commits: null
+  commit_count_8m: 0
+  contributors: null
+  reference_files:
+    root: null
+    ai: null
+    cursor: null
+    docs: null
+    tasks: null
+  todo_open: 0
+  todo_closed: 0
+  deployment: null
+  plans_count: 0
+  ai_docs_count: 0
+  scanned_at: ""
+  mark: archived
+  note: |-
+    line one
+    line "two"
+`},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		if err := WriteProjects(&b, tt.format, outputFixture(), tt.cols); err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.format, b.String(), tt.want)
+		}
+	}
+
+	// No projects is an empty list, not null.
+	var b bytes.Buffer
+	if err := WriteProjects(&b, FormatJSON, nil, nil); err != nil || b.String() != "[]\n" {
+		t.Errorf("json of no projects = %q, %v", b.String(), err)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{.Name}}{{"\t"}}{{.Path}}`, "api\t/dev/api\nweb, app\t/dev/web app\n"},
+		{`{{.Name}}: {{join .TechStack "+"}}`, "api: go\nweb, app: node+react\n"},
+		{`{{field . "status"}} {{field . "commits"}}{{"\n"}}`, "active 12\narchived 0\n"},
+		{`{{json .Tags}}`, "[\"acme\"]\nnull\n"},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseTemplate(tt.text)
+		if err != nil {
+			t.Errorf("ParseTemplate(%q): %v", tt.text, err)
+			continue
+		}
+		var b bytes.Buffer
+		for _, p := range outputFixture() {
+			if err := ExecTemplate(&b, tmpl, p); err != nil {
+				t.Fatalf("ExecTemplate(%q): %v", tt.text, err)
+			}
+		}
+		if b.String() != tt.want {
+			t.Errorf("template %q = %q, want %q", tt.text, b.String(), tt.want)
+		}
+	}
+
+	if _, err := ParseTemplate("{{.Name"); err == nil {
+		t.Error("ParseTemplate accepted an unclosed action")
+	}
+}
@@ -3,55 +3,99 @@ package display
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/peeomid/prj/internal/config"
 	"github.com/peeomid/prj/internal/project"
 )
 
-// PrintSummary renders a status report with counts and highlights.
-func PrintSummary(projects []*project.Project) {
-	if len(projects) == 0 {
-		fmt.Println("No projects scanned yet. Run: prj scan")
-		return
-	}
+// Summary is the data behind "prj status".
+type Summary struct {
+	Total       int              `json:"total"`
+	ByStatus    []Count          `json:"by_status"`
+	ByType      []Count          `json:"by_type"`
+	Own         int              `json:"own"`
+	Forks       int              `json:"forks"`
+	Missing     int              `json:"missing"`
+	Focus       []string         `json:"focus,omitempty"`
+	Maintained  []string         `json:"maintained,omitempty"`
+	MostRecent  []SummaryProject `json:"most_recent"`
+	StalledDays int              `json:"stalled_days"` // global; folders can override it
+	Stalled     []SummaryProject `json:"stalled"`
+}
+
+// Count is one line of a breakdown.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
 
-	fmt.Printf("\n  %s  %d projects\n\n", Bold("Project Summary"), len(projects))
+// SummaryProject is a project listed in a Summary.
+type SummaryProject struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	LastCommit string `json:"last_commit"`
+	// StalledDays is the window the project was found stalled by (only in
+	// Stalled).
+	StalledDays int `json:"stalled_days,omitempty"`
 
-	// By status
+	p *project.Project
+}
+
+func summaryProject(p *project.Project) SummaryProject {
+	return SummaryProject{Name: p.Name, Path: p.Path, Status: p.EffectiveStatus(), LastCommit: p.LastCommitDate, p: p}
+}
+
+// Summarize computes the status report. The stalled window comes from cfg,
+// per folder.
+func Summarize(projects []*project.Project, cfg *config.Config) *Summary {
+	s := &Summary{Total: len(projects), StalledDays: cfg.StalledDays}
+
+	// By status: built-in statuses in ladder order, then custom ones from
+	// status_rules.
 	statusCounts := map[string]int{}
 	for _, p := range projects {
-		statusCounts[p.Status]++
+		statusCounts[p.EffectiveStatus()]++
 	}
-	fmt.Printf("  %s\n", Bold("By Status"))
-	for _, s := range []string{"active", "wip", "recent", "paused"} {
-		if c, ok := statusCounts[s]; ok {
-			fmt.Printf("    %s  %d\n", StatusColor(s), c)
+	builtin := []string{"active", "wip", "recent", "paused", "idea", "archived", "abandoned"}
+	for _, st := range builtin {
+		if c, ok := statusCounts[st]; ok {
+			s.ByStatus = append(s.ByStatus, Count{st, c})
 		}
+		delete(statusCounts, st)
+	}
+	for _, st := range sortedKeys(statusCounts) {
+		s.ByStatus = append(s.ByStatus, Count{st, statusCounts[st]})
 	}
 
-	// By type
 	typeCounts := map[string]int{}
 	for _, p := range projects {
 		typeCounts[p.InferredType]++
 	}
-	fmt.Printf("\n  %s\n", Bold("By Type"))
-	types := sortedKeys(typeCounts)
-	for _, t := range types {
-		fmt.Printf("    %-15s %d\n", t, typeCounts[t])
+	for _, t := range sortedKeys(typeCounts) {
+		s.ByType = append(s.ByType, Count{t, typeCounts[t]})
 	}
 
-	// Ownership
-	ownCount, forkCount := 0, 0
 	for _, p := range projects {
 		if p.IsFork {
-			forkCount++
+			s.Forks++
 		} else {
-			ownCount++
+			s.Own++
+		}
+		// Repo deleted or folder no longer tracked
+		if p.Missing != "" {
+			s.Missing++
+		}
+		switch p.Mark {
+		case project.MarkFocus:
+			s.Focus = append(s.Focus, p.Name)
+		case project.MarkMaintained:
+			s.Maintained = append(s.Maintained, p.Name)
 		}
 	}
-	fmt.Printf("\n  %s\n", Bold("Ownership"))
-	fmt.Printf("    Own:    %d\n", ownCount)
-	fmt.Printf("    Forks:  %d\n", forkCount)
 
 	// Top 5 most recent
 	sorted := make([]*project.Project, len(projects))
@@ -59,37 +103,127 @@ func PrintSummary(projects []*project.Project) {
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].LastCommitDate > sorted[j].LastCommitDate
 	})
-
-	fmt.Printf("\n  %s\n", Bold("Most Recent"))
 	limit := 5
 	if len(sorted) < limit {
 		limit = len(sorted)
 	}
 	for _, p := range sorted[:limit] {
-		fmt.Printf("    %-25s %s  %s\n", p.Name, StatusColor(p.Status), formatAge(p.LastCommitDate))
+		s.MostRecent = append(s.MostRecent, summaryProject(p))
 	}
 
-	// Stalled (no commits in 6+ months)
-	sixMonthsAgo := time.Now().AddDate(0, -6, 0)
-	var stalled []*project.Project
+	// Stalled (no commits in stalled_days), unless marked as meant to be quiet
 	for _, p := range projects {
+		if p.Dormant() {
+			continue
+		}
+		days := cfg.ThresholdsFor(p.Path).StalledDays
+		stalled := summaryProject(p)
+		stalled.StalledDays = days
 		if p.LastCommitDate == "" {
-			stalled = append(stalled, p)
+			s.Stalled = append(s.Stalled, stalled)
 			continue
 		}
 		t, err := time.Parse(time.RFC3339, p.LastCommitDate)
-		if err == nil && t.Before(sixMonthsAgo) {
-			stalled = append(stalled, p)
+		if err == nil && t.Before(time.Now().AddDate(0, 0, -days)) {
+			s.Stalled = append(s.Stalled, stalled)
 		}
 	}
-	if len(stalled) > 0 {
-		fmt.Printf("\n  %s  (%d)\n", Bold("Stalled (6+ months)"), len(stalled))
+	return s
+}
+
+// Rows flattens the summary into section/key/value rows for csv, tsv and
+// markdown output.
+func (s *Summary) Rows() [][]string {
+	rows := [][]string{{"summary", "total", strconv.Itoa(s.Total)}}
+	for _, c := range s.ByStatus {
+		rows = append(rows, []string{"status", c.Name, strconv.Itoa(c.Count)})
+	}
+	for _, c := range s.ByType {
+		rows = append(rows, []string{"type", c.Name, strconv.Itoa(c.Count)})
+	}
+	rows = append(rows,
+		[]string{"ownership", "own", strconv.Itoa(s.Own)},
+		[]string{"ownership", "forks", strconv.Itoa(s.Forks)},
+		[]string{"summary", "missing", strconv.Itoa(s.Missing)},
+	)
+	for _, n := range s.Focus {
+		rows = append(rows, []string{"focus", n, ""})
+	}
+	for _, n := range s.Maintained {
+		rows = append(rows, []string{"maintained", n, ""})
+	}
+	for _, p := range s.MostRecent {
+		rows = append(rows, []string{"most_recent", p.Name, p.LastCommit})
+	}
+	for _, p := range s.Stalled {
+		rows = append(rows, []string{"stalled", p.Name, p.LastCommit})
+	}
+	return rows
+}
+
+// PrintSummary renders a status report with counts and highlights.
+func PrintSummary(s *Summary) {
+	if s.Total == 0 {
+		fmt.Println("No projects scanned yet. Run: prj scan")
+		return
+	}
+
+	fmt.Printf("\n  %s  %d projects\n\n", Bold("Project Summary"), s.Total)
+
+	fmt.Printf("  %s\n", Bold("By Status"))
+	for _, c := range s.ByStatus {
+		fmt.Printf("    %s  %d\n", StatusColor(c.Name), c.Count)
+	}
+
+	fmt.Printf("\n  %s\n", Bold("By Type"))
+	for _, c := range s.ByType {
+		fmt.Printf("    %-15s %d\n", c.Name, c.Count)
+	}
+
+	fmt.Printf("\n  %s\n", Bold("Ownership"))
+	fmt.Printf("    Own:    %d\n", s.Own)
+	fmt.Printf("    Forks:  %d\n", s.Forks)
+
+	if s.Missing > 0 {
+		fmt.Printf("\n  %s  %d  %s\n", Red("Missing"), s.Missing, Gray("(run: prj prune)"))
+	}
+
+	if len(s.Focus) > 0 {
+		fmt.Printf("\n  %s  %s\n", Bold("Focus ★"), strings.Join(s.Focus, ", "))
+	}
+	if len(s.Maintained) > 0 {
+		fmt.Printf("\n  %s  %s\n", Bold("Maintained"), strings.Join(s.Maintained, ", "))
+	}
+
+	fmt.Printf("\n  %s\n", Bold("Most Recent"))
+	for _, p := range s.MostRecent {
+		fmt.Printf("    %-25s %s  %s\n", p.Name, StatusLabel(p.p), FormatAge(p.LastCommit))
+	}
+
+	if len(s.Stalled) > 0 {
+		// One window for all goes in the header; with per-folder windows
+		// each project shows its own.
+		shared := s.Stalled[0].StalledDays
+		for _, p := range s.Stalled {
+			if p.StalledDays != shared {
+				shared = 0
+			}
+		}
+		header := "Stalled"
+		if shared > 0 {
+			header = fmt.Sprintf("Stalled (%d+ days)", shared)
+		}
+		fmt.Printf("\n  %s  (%d)\n", Bold(header), len(s.Stalled))
 		limit := 10
-		if len(stalled) < limit {
-			limit = len(stalled)
+		if len(s.Stalled) < limit {
+			limit = len(s.Stalled)
 		}
-		for _, p := range stalled[:limit] {
-			fmt.Printf("    %s  %s\n", Gray(p.Name), Gray(formatAge(p.LastCommitDate)))
+		for _, p := range s.Stalled[:limit] {
+			if shared > 0 {
+				fmt.Printf("    %s  %s\n", Gray(p.Name), Gray(FormatAge(p.LastCommit)))
+				continue
+			}
+			fmt.Printf("    %s  %s  %s\n", Gray(p.Name), Gray(FormatAge(p.LastCommit)), Gray(fmt.Sprintf("(%d+ days)", p.StalledDays)))
 		}
 	}
 
@@ -10,39 +10,116 @@ import (
 	"github.com/rodaine/table"
 )
 
-// PrintTable renders projects as a table.
-func PrintTable(projects []*project.Project) {
+// tablePadding is the space rodaine/table puts after every column.
+const tablePadding = 2
+
+// minFlexWidth is as far as a flexible column shrinks to fit the terminal.
+const minFlexWidth = 8
+
+// PrintTable renders projects as a table with the given columns (nil for
+// DefaultTableColumns), shrinking flexible columns to fit the terminal.
+func PrintTable(projects []*project.Project, cols []Column) {
 	if len(projects) == 0 {
 		fmt.Println("No projects found.")
 		return
 	}
+	if cols == nil {
+		cols, _ = Columns(DefaultTableColumns)
+	}
 
-	tbl := table.New("Name", "Type", "Status", "Tech", "Last Commit", "Commits(8m)")
-	tbl.WithWriter(os.Stdout)
+	printTable(projects, cols, fitWidths(cols, cellRows(projects, cols), TerminalWidth()))
+	fmt.Printf("\n%s projects\n", Bold(fmt.Sprintf("%d", len(projects))))
+}
 
+// cellRows renders the table cells of projects, uncolored and unfitted.
+func cellRows(projects []*project.Project, cols []Column) [][]string {
+	rows := make([][]string, len(projects))
+	for i, p := range projects {
+		rows[i] = make([]string, len(cols))
+		for j, c := range cols {
+			rows[i][j] = c.Cell(p)
+		}
+	}
+	return rows
+}
+
+// printTable prints projects with every column exactly widths wide, so
+// tables printed one after another line up.
+func printTable(projects []*project.Project, cols []Column, widths []int) {
+	headers := make([]interface{}, len(cols))
+	for j, c := range cols {
+		headers[j] = pad(elide(c.Header, widths[j], false), widths[j])
+	}
+	tbl := table.New(headers...)
+	tbl.WithWriter(os.Stdout)
+	tbl.WithPadding(tablePadding)
+	tbl.WithWidthFunc(textWidth)
 	for _, p := range projects {
-		tech := strings.Join(p.TechStack, ",")
-		if len(tech) > 20 {
-			tech = tech[:17] + "..."
+		cells := make([]interface{}, len(cols))
+		for j, c := range cols {
+			s := elide(c.Cell(p), widths[j], c.ElideLeft)
+			if c.Paint != nil && s != "" {
+				s = c.Paint(p, s)
+			}
+			cells[j] = pad(s, widths[j])
 		}
+		tbl.AddRow(cells...)
+	}
+	tbl.Print()
+}
 
-		lastCommit := formatAge(p.LastCommitDate)
+func pad(s string, width int) string {
+	if n := width - textWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
 
-		tbl.AddRow(
-			p.Name,
-			p.InferredType,
-			StatusColor(p.Status),
-			tech,
-			lastCommit,
-			p.CommitCount8M,
-		)
+// fitWidths returns each column's width: its widest cell, with flexible
+// columns narrowed (widest first) until the table fits in limit. A limit
+// of 0 means no limit.
+func fitWidths(cols []Column, rows [][]string, limit int) []int {
+	widths := make([]int, len(cols))
+	floors := make([]int, len(cols))
+	for j, c := range cols {
+		widths[j] = textWidth(c.Header)
+		for _, r := range rows {
+			if w := textWidth(r[j]); w > widths[j] {
+				widths[j] = w
+			}
+		}
+		floors[j] = widths[j]
+		if c.Flex {
+			floors[j] = min(widths[j], max(textWidth(c.Header), minFlexWidth))
+		}
+	}
+	if limit <= 0 {
+		return widths
 	}
 
-	tbl.Print()
-	fmt.Printf("\n%s projects\n", Bold(fmt.Sprintf("%d", len(projects))))
+	total := 0
+	for _, w := range widths {
+		total += w + tablePadding
+	}
+	for total > limit {
+		widest := -1
+		for j := range cols {
+			if widths[j] > floors[j] && (widest < 0 || widths[j] > widths[widest]) {
+				widest = j
+			}
+		}
+		if widest < 0 {
+			break // nothing left to give; let the terminal wrap
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
 }
 
-func formatAge(dateStr string) string {
+// FormatAge renders an RFC 3339 date as "today", "3d ago", "2mo ago" and so
+// on, or "never" when empty.
+func FormatAge(dateStr string) string {
 	if dateStr == "" {
 		return "never"
 	}
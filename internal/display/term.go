@@ -0,0 +1,54 @@
+package display
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// TerminalWidth is the width of the terminal stdout is attached to, or
+// $COLUMNS, or 0 when neither is known (output is piped): don't truncate.
+func TerminalWidth() int {
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			return w
+		}
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// textWidth counts the terminal cells s takes up, skipping color codes:
+// wide glyphs such as CJK take two.
+func textWidth(s string) int {
+	return runewidth.StringWidth(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// elide shortens s to width cells, marking the cut with "…" at the end,
+// or at the start when left is set.
+func elide(s string, width int, left bool) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+	if !left {
+		return runewidth.Truncate(s, width, "…")
+	}
+	r := []rune(s)
+	w := 1 // the "…"
+	i := len(r)
+	for i > 0 && w+runewidth.RuneWidth(r[i-1]) <= width {
+		i--
+		w += runewidth.RuneWidth(r[i])
+	}
+	return "…" + string(r[i:])
+}
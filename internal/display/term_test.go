@@ -0,0 +1,47 @@
+package display
+
+import "testing"
+
+func TestTextWidth(t *testing.T) {
+	tests := map[string]int{
+		"api":                   3,
+		"\x1b[32mactive\x1b[0m": 6,
+		"日本語":                   6,
+		"é":                     1,
+		"":                      0,
+	}
+	for s, want := range tests {
+		if got := textWidth(s); got != want {
+			t.Errorf("textWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestElide(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		left  bool
+		want  string
+	}{
+		{"project", 10, false, "project"},
+		{"project", 7, false, "project"},
+		{"project", 5, false, "proj…"},
+		{"project", 5, true, "…ject"},
+		{"project", 1, false, "…"},
+		// Wide glyphs take two cells and are never split.
+		{"日本語です", 6, false, "日本…"},
+		{"日本語です", 6, true, "…です"},
+		{"日本語です", 5, false, "日本…"},
+		{"日本語です", 4, true, "…す"},
+	}
+	for _, tt := range tests {
+		got := elide(tt.s, tt.width, tt.left)
+		if got != tt.want {
+			t.Errorf("elide(%q, %d, %v) = %q, want %q", tt.s, tt.width, tt.left, got, tt.want)
+		}
+		if textWidth(got) > tt.width && tt.width > 0 {
+			t.Errorf("elide(%q, %d, %v) = %q is %d cells wide", tt.s, tt.width, tt.left, got, textWidth(got))
+		}
+	}
+}
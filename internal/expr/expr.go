@@ -0,0 +1,251 @@
+// Package expr implements the small boolean expression language used for
+// user-defined status rules and "prj list --where" queries.
+//
+// An expression combines comparisons with and/or/not (or &&, ||, !) and
+// parentheses:
+//
+//	days_since_commit < 14 && todo_open > 0
+//	status in (active, wip) and tech ~ react and not fork
+//	all recent_messages ~ "^(bump|chore\(deps\))"
+//
+// Comparisons are field OP value, where OP is one of == (or =), !=, <, <=,
+// >, >=, ~ (regex match) and !~, or "in (a, b, ...)". Values are bare words
+// or quoted strings. String comparisons and regexes are case-insensitive.
+// List fields match if any element does; prefix with "all" to require every
+// element to match. Date fields compare against a date (2026-01-31) or an
+// age (30d, 2w, 6m, 1y), meaning that long before now. A bare field is true
+// when it is true, non-zero or non-empty.
+package expr
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kind is the type of value a field holds.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindNumber
+	KindBool
+	KindList
+	KindDate
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "bool"
+	case KindList:
+		return "list"
+	case KindDate:
+		return "date"
+	default:
+		return "string"
+	}
+}
+
+// Env resolves field names to values during evaluation. Values must match
+// the Kind the field was compiled with: string, float64, bool, []string or
+// time.Time (the zero time meaning "never").
+type Env interface {
+	Lookup(name string) any
+}
+
+// Expr is a compiled expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// String returns the source the expression was compiled from.
+func (e *Expr) String() string { return e.src }
+
+// Eval reports whether env satisfies the expression.
+func (e *Expr) Eval(env Env) bool { return e.root.eval(env) }
+
+type node interface {
+	eval(env Env) bool
+}
+
+type orNode struct{ l, r node }
+
+func (n *orNode) eval(env Env) bool { return n.l.eval(env) || n.r.eval(env) }
+
+type andNode struct{ l, r node }
+
+func (n *andNode) eval(env Env) bool { return n.l.eval(env) && n.r.eval(env) }
+
+type notNode struct{ x node }
+
+func (n *notNode) eval(env Env) bool { return !n.x.eval(env) }
+
+type fieldNode struct{ name string }
+
+func (n *fieldNode) eval(env Env) bool {
+	switch v := env.Lookup(n.name).(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []string:
+		return len(v) > 0
+	case time.Time:
+		return !v.IsZero()
+	}
+	return false
+}
+
+type ageSpec struct {
+	days, months, years int
+	set                 bool
+}
+
+type literal struct {
+	text string
+	num  float64
+	b    bool
+	date time.Time
+	age  ageSpec
+	re   *regexp.Regexp
+}
+
+func (l literal) when() time.Time {
+	if l.age.set {
+		return time.Now().AddDate(-l.age.years, -l.age.months, -l.age.days)
+	}
+	return l.date
+}
+
+type cmpNode struct {
+	field string
+	kind  Kind
+	op    string
+	quant string
+	lit   literal
+	in    []literal
+}
+
+func (n *cmpNode) eval(env Env) bool {
+	v := env.Lookup(n.field)
+	if list, ok := v.([]string); ok {
+		if n.quant == "all" {
+			if len(list) == 0 {
+				return false
+			}
+			for _, s := range list {
+				if !n.match(s) {
+					return false
+				}
+			}
+			return true
+		}
+		// "!=" and "!~" on a list mean no element matches.
+		if n.op == "!=" || n.op == "!~" {
+			for _, s := range list {
+				if !n.match(s) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, s := range list {
+			if n.match(s) {
+				return true
+			}
+		}
+		return false
+	}
+	return n.match(v)
+}
+
+// match compares a single (scalar) value.
+func (n *cmpNode) match(v any) bool {
+	if n.op == "in" {
+		for _, l := range n.in {
+			if compare(v, "==", l) {
+				return true
+			}
+		}
+		return false
+	}
+	return compare(v, n.op, n.lit)
+}
+
+func compare(v any, op string, l literal) bool {
+	if l.re != nil {
+		s := toString(v)
+		if op == "!~" {
+			return !l.re.MatchString(s)
+		}
+		return l.re.MatchString(s)
+	}
+	switch x := v.(type) {
+	case float64:
+		return ordered(cmpFloat(x, l.num), op)
+	case bool:
+		if op == "!=" {
+			return x != l.b
+		}
+		return x == l.b
+	case time.Time:
+		if x.IsZero() {
+			// A project that never committed matches nothing but "!=".
+			return op == "!="
+		}
+		w := l.when()
+		if l.age.set || len(l.text) == 10 {
+			// Compare by calendar day for dates and ages.
+			x, w = day(x), day(w)
+		}
+		return ordered(x.Compare(w), op)
+	default:
+		return ordered(strings.Compare(strings.ToLower(toString(v)), strings.ToLower(l.text)), op)
+	}
+}
+
+func ordered(c int, op string) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func toString(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = map[string]Kind{
+	"a":       KindBool,
+	"b":       KindBool,
+	"c":       KindBool,
+	"name":    KindString,
+	"status":  KindString,
+	"commits": KindNumber,
+	"tech":    KindList,
+	"tags":    KindList,
+	"last":    KindDate,
+}
+
+type env map[string]any
+
+func (e env) Lookup(name string) any { return e[name] }
+
+func daysAgo(n int) time.Time { return time.Now().AddDate(0, 0, -n) }
+
+func TestEval(t *testing.T) {
+	base := env{
+		"a": false, "b": false, "c": false,
+		"name":    "My-API",
+		"status":  "active",
+		"commits": 25.0,
+		"tech":    []string{"go", "react"},
+		"tags":    []string{},
+		"last":    daysAgo(10),
+	}
+	with := func(kv ...any) env {
+		e := env{}
+		for k, v := range base {
+			e[k] = v
+		}
+		for i := 0; i < len(kv); i += 2 {
+			e[kv[i].(string)] = kv[i+1]
+		}
+		return e
+	}
+
+	tests := []struct {
+		src  string
+		env  env
+		want bool
+	}{
+		// Precedence: not binds tightest, then and, then or.
+		{"not a and b or c", with("a", false, "b", true), true},
+		{"not a and b or c", with("a", true, "b", true), false},
+		{"not a and b or c", with("a", true, "c", true), true},
+		{"not (a and b) or c", with("a", true, "b", false), true},
+		{"a or b and c", with("a", true), true},
+		{"(a or b) and c", with("a", true), false},
+		{"!a && b || c", with("b", true), true},
+
+		// Bare fields are truthy.
+		{"a", with("a", true), true},
+		{"tags", base, false},
+		{"tech", base, true},
+		{"last", with("last", time.Time{}), false},
+
+		// Strings compare case-insensitively.
+		{"status == ACTIVE", base, true},
+		{"status = active", base, true},
+		{"status != active", base, false},
+		{`name == "my-api"`, base, true},
+
+		// in (...) and not in (...).
+		{"status in (wip, active)", base, true},
+		{"status in (wip, paused)", base, false},
+		{"status not in (wip, paused)", base, true},
+		{"tech in (rust, react)", base, true},
+
+		// Regexes; anchors and groups need quotes.
+		{`name ~ "^my"`, base, true},
+		{`name ~ "api$"`, base, true},
+		{"name ~ api", base, true},
+		{"name !~ api", base, false},
+		{`name ~ "^(x|my)-"`, base, true},
+
+		// Numbers.
+		{"commits > 20", base, true},
+		{"commits >= 25", base, true},
+		{"commits < 25", base, false},
+		{"commits == 25", base, true},
+
+		// Lists match if any element does, all requires every element.
+		{`tech ~ "^re"`, base, true},
+		{`all tech ~ "^re"`, base, false},
+		{`all tech ~ "^(go|react)$"`, base, true},
+		{"any tech == go", base, true},
+		{"tech == rust", base, false},
+		// != and !~ on a list mean no element matches.
+		{"tech != go", base, false},
+		{"tech != rust", base, true},
+		{`tech !~ "^r"`, base, false},
+		// all over an empty list is false.
+		{"all tags ~ x", base, false},
+
+		// Dates against ages: "last < 30d" means longer ago than 30 days.
+		{"last > 30d", base, true},
+		{"last < 30d", base, false},
+		{"last < 1w", base, true},
+		{"last > 2w", base, true},
+		{"last < 1m", base, false},
+		{"last > 1y", base, true},
+		// Dates against a calendar day.
+		{"last > 2020-01-31", base, true},
+		{"last < 2020-01-31", base, false},
+		{"last == 2026-01-31", with("last", time.Date(2026, 1, 31, 15, 0, 0, 0, time.Local)), true},
+		{"last <= 2026-01-31", with("last", time.Date(2026, 2, 1, 0, 30, 0, 0, time.Local)), false},
+		// A project that never committed only matches !=.
+		{"last < 30d", with("last", time.Time{}), false},
+		{"last > 30d", with("last", time.Time{}), false},
+		{"last != 2026-01-31", with("last", time.Time{}), true},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.src, testFields)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.src, err)
+			continue
+		}
+		if got := e.Eval(tt.env); got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string
+	}{
+		{"", 1, "empty expression"},
+		{"statsu == active", 1, `unknown field "statsu" (did you mean "status"?)`},
+		{"status == active and tehc ~ react", 22, `unknown field "tehc" (did you mean "tech"?)`},
+		{"commits > many", 11, "expected a number"},
+		{"a == maybe", 6, "expected true or false"},
+		{"last < soon", 8, "expected a date"},
+		{"commits ~ 3", 9, "number fields support"},
+		{"all status ~ x", 5, `"all" only applies to list fields`},
+		{"status in active", 11, `expected "(" after in`},
+		{"status in (a b)", 14, `expected "," or ")"`},
+		{`name ~ "("`, 8, "bad regex"},
+		{"(a or b", 8, "expected"},
+		{"a b", 3, "unexpected"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src, testFields)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("Compile(%q) = %v, want an *Error", tt.src, err)
+			continue
+		}
+		if e.Pos != tt.pos || !strings.Contains(e.Msg, tt.msg) {
+			t.Errorf("Compile(%q) = column %d: %s; want column %d containing %q", tt.src, e.Pos, e.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestErrorShow(t *testing.T) {
+	src := "status == active and tehc ~ react"
+	_, err := Compile(src, testFields)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Compile(%q) = %v, want an *Error", src, err)
+	}
+	want := `column 22: unknown field "tehc" (did you mean "tech"?)` + "\n" +
+		"  status == active and tehc ~ react\n" +
+		"                       ^"
+	if got := e.Show(src); got != want {
+		t.Errorf("Show:\n%s\nwant:\n%s", got, want)
+	}
+}
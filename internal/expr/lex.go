@@ -0,0 +1,100 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based column
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators, longest first so "<=" wins over "<".
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "=", "~", "!"}
+
+// isWordRune reports whether r can appear in a bare word. Words cover field
+// names, numbers, dates (2026-01-31), ages (30d) and values like go-app.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./:@+#", r)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", pos})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", pos})
+			i++
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", pos})
+			i++
+		case r == '"' || r == '\'':
+			quote := r
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != quote; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) && (runes[j+1] == quote || runes[j+1] == '\\') {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, &Error{Pos: pos, Msg: "unterminated string"}
+			}
+			toks = append(toks, token{tokString, b.String(), pos})
+			i = j + 1
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			toks = append(toks, token{tokWord, string(runes[i:j]), pos})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					toks = append(toks, token{tokOp, op, pos})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(runes) + 1})
+	return toks, nil
+}
@@ -0,0 +1,366 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is a parse or type error at a 1-based column of the source.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+// Show renders the error under the source with a caret at its column:
+//
+//	column 22: unknown field "tehc" (did you mean "tech"?)
+//	  status == active and tehc ~ react
+//	                       ^
+func (e *Error) Show(src string) string {
+	pad := strings.Repeat(" ", e.Pos-1)
+	return fmt.Sprintf("%s\n  %s\n  %s^", e.Error(), src, pad)
+}
+
+type parser struct {
+	toks   []token
+	i      int
+	fields map[string]Kind
+}
+
+// Compile parses src and type-checks it against fields, the names an Env
+// will be able to resolve and the kind of value each returns.
+func Compile(src string, fields map[string]Kind) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 1 {
+		return nil, &Error{Pos: 1, Msg: "empty expression"}
+	}
+	p := &parser{toks: toks, fields: fields}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return &Expr{src: src, root: n}, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword reports whether t is the given case-insensitive bare word.
+func keyword(t token, kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !(t.kind == tokOp && t.text == "||") && !keyword(t, "or") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if !(t.kind == tokOp && t.text == "&&") && !keyword(t, "and") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if (t.kind == tokOp && t.text == "!") || keyword(t, "not") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x}, nil
+	}
+	if t.kind == tokLParen {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokRParen {
+			return nil, &Error{Pos: c.pos, Msg: fmt.Sprintf("expected \")\", got %s", c)}
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	quant := ""
+	if t := p.peek(); keyword(t, "all") || keyword(t, "any") {
+		if n := p.toks[p.i+1]; n.kind == tokWord {
+			quant = strings.ToLower(p.next().text)
+		}
+	}
+
+	ft := p.next()
+	if ft.kind != tokWord {
+		return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("expected a field name, got %s", ft)}
+	}
+	name := strings.ToLower(ft.text)
+	kind, ok := p.fields[name]
+	if !ok {
+		if s := suggest(name, p.fields); s != "" {
+			return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("unknown field %q (did you mean %q?)", ft.text, s)}
+		}
+		return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("unknown field %q (known: %s)", ft.text, strings.Join(fieldNames(p.fields), ", "))}
+	}
+	if quant != "" && kind != KindList {
+		return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("%q only applies to list fields; %s is a %s", quant, name, kind)}
+	}
+
+	// Bare field: truthiness.
+	opTok := p.peek()
+	negIn := false
+	if keyword(opTok, "not") && keyword(p.toks[p.i+1], "in") {
+		p.next()
+		negIn = true
+		opTok = p.peek()
+	}
+	isIn := keyword(opTok, "in")
+	if opTok.kind != tokOp && !isIn {
+		if quant != "" {
+			return nil, &Error{Pos: opTok.pos, Msg: fmt.Sprintf("expected an operator after %s", name)}
+		}
+		return &fieldNode{name: name}, nil
+	}
+	if opTok.kind == tokOp && (opTok.text == "!" || opTok.text == "&&" || opTok.text == "||") {
+		return &fieldNode{name: name}, nil
+	}
+	p.next()
+
+	c := &cmpNode{field: name, kind: kind, quant: quant}
+	if isIn {
+		c.op = "in"
+		lits, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		for _, lt := range lits {
+			l, err := p.literal(lt, kind, "==")
+			if err != nil {
+				return nil, err
+			}
+			c.in = append(c.in, l)
+		}
+		if negIn {
+			return &notNode{c}, nil
+		}
+		return c, nil
+	}
+
+	c.op = opTok.text
+	if c.op == "=" {
+		c.op = "=="
+	}
+	if err := checkOp(kind, c.op); err != nil {
+		return nil, &Error{Pos: opTok.pos, Msg: fmt.Sprintf("%s: %s", name, err)}
+	}
+	lt := p.next()
+	if lt.kind != tokWord && lt.kind != tokString {
+		return nil, &Error{Pos: lt.pos, Msg: fmt.Sprintf("expected a value after %s, got %s", c.op, lt)}
+	}
+	l, err := p.literal(lt, kind, c.op)
+	if err != nil {
+		return nil, err
+	}
+	c.lit = l
+	return c, nil
+}
+
+func (p *parser) parseList() ([]token, error) {
+	open := p.next()
+	if open.kind != tokLParen {
+		return nil, &Error{Pos: open.pos, Msg: fmt.Sprintf("expected \"(\" after in, got %s", open)}
+	}
+	var items []token
+	for {
+		t := p.next()
+		if t.kind != tokWord && t.kind != tokString {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a value in list, got %s", t)}
+		}
+		items = append(items, t)
+		sep := p.next()
+		if sep.kind == tokRParen {
+			return items, nil
+		}
+		if sep.kind != tokComma {
+			return nil, &Error{Pos: sep.pos, Msg: fmt.Sprintf("expected \",\" or \")\", got %s", sep)}
+		}
+	}
+}
+
+// literal parses a value token according to the kind of field it is
+// compared with.
+func (p *parser) literal(t token, kind Kind, op string) (literal, error) {
+	l := literal{text: t.text}
+	switch {
+	case op == "~" || op == "!~":
+		re, err := regexp.Compile("(?i)" + t.text)
+		if err != nil {
+			return l, &Error{Pos: t.pos, Msg: fmt.Sprintf("bad regex %q: %s", t.text, err)}
+		}
+		l.re = re
+	case kind == KindNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return l, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a number, got %s", t)}
+		}
+		l.num = n
+	case kind == KindBool:
+		switch strings.ToLower(t.text) {
+		case "true", "yes":
+			l.b = true
+		case "false", "no":
+			l.b = false
+		default:
+			return l, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected true or false, got %s", t)}
+		}
+	case kind == KindDate:
+		if d, ok := parseAge(t.text); ok {
+			l.age = d
+			break
+		}
+		tm, err := parseDate(t.text)
+		if err != nil {
+			return l, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a date (2026-01-31) or age (30d, 2w, 6m), got %s", t)}
+		}
+		l.date = tm
+	}
+	return l, nil
+}
+
+func checkOp(kind Kind, op string) error {
+	allowed := map[Kind]string{
+		KindString: "== != < <= > >= ~ !~",
+		KindNumber: "== != < <= > >=",
+		KindBool:   "== !=",
+		KindList:   "== != ~ !~",
+		KindDate:   "== != < <= > >=",
+	}[kind]
+	for _, a := range strings.Fields(allowed) {
+		if a == op {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s fields support %s, not %s", kind, allowed, op)
+}
+
+// parseAge reads "30d", "2w", "6m" or "1y" as a span of days back from now.
+func parseAge(s string) (ageSpec, bool) {
+	if len(s) < 2 {
+		return ageSpec{}, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return ageSpec{}, false
+	}
+	switch strings.ToLower(s[len(s)-1:]) {
+	case "d":
+		return ageSpec{days: n, set: true}, true
+	case "w":
+		return ageSpec{days: 7 * n, set: true}, true
+	case "m":
+		return ageSpec{months: n, set: true}, true
+	case "y":
+		return ageSpec{years: n, set: true}, true
+	}
+	return ageSpec{}, false
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// suggest returns the field closest to a misspelt name, if any is within
+// two edits.
+func suggest(name string, fields map[string]Kind) string {
+	best, bestDist := "", 3
+	for _, f := range fieldNames(fields) {
+		if d := distance(name, f); d < bestDist {
+			best, bestDist = f, d
+		}
+	}
+	return best
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func fieldNames(fields map[string]Kind) []string {
+	names := make([]string, 0, len(fields))
+	for n := range fields {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
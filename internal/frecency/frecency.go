@@ -0,0 +1,114 @@
+// Package frecency remembers which projects the user jumps into, so that
+// "prj cd" can prefer the ones used most often and most recently. Scoring
+// follows zoxide: every visit adds 1 to a path's rank, the rank is weighted
+// by how long ago the last visit was, and old entries decay away.
+package frecency
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+)
+
+// MaxRank bounds the sum of all ranks. Past it every rank is scaled down
+// and entries falling below 1 are forgotten.
+const MaxRank = 10000
+
+// Entry is one visited project path.
+type Entry struct {
+	Rank      float64 `json:"rank"`
+	LastVisit int64   `json:"last_visit"` // Unix seconds
+}
+
+// DB maps project paths to their entries.
+type DB map[string]*Entry
+
+// Path returns the database location, ~/.prj/frecency.json.
+func Path() string {
+	return filepath.Join(config.Dir(), "frecency.json")
+}
+
+// Load reads the database. A missing file is an empty database.
+func Load() (DB, error) {
+	db := DB{}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return db, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Visit records a jump into path.
+func Visit(path string, now time.Time) error {
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	db, err := Load()
+	if err != nil {
+		return err
+	}
+	e := db[path]
+	if e == nil {
+		e = &Entry{}
+		db[path] = e
+	}
+	e.Rank++
+	e.LastVisit = now.Unix()
+	db.age()
+
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(Path(), data, 0644)
+}
+
+// age scales every rank down once their sum passes MaxRank.
+func (db DB) age() {
+	var total float64
+	for _, e := range db {
+		total += e.Rank
+	}
+	if total <= MaxRank {
+		return
+	}
+	factor := 0.9 * MaxRank / total
+	for path, e := range db {
+		e.Rank *= factor
+		if e.Rank < 1 {
+			delete(db, path)
+		}
+	}
+}
+
+// Score is path's frecency at now: its rank, boosted when the last visit
+// was recent. Never-visited paths score 0.
+func (db DB) Score(path string, now time.Time) float64 {
+	e := db[path]
+	if e == nil {
+		return 0
+	}
+	switch since := now.Sub(time.Unix(e.LastVisit, 0)); {
+	case since < time.Hour:
+		return e.Rank * 4
+	case since < 24*time.Hour:
+		return e.Rank * 2
+	case since < 7*24*time.Hour:
+		return e.Rank / 2
+	default:
+		return e.Rank / 4
+	}
+}
@@ -0,0 +1,77 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LockTimeout is how long Lock waits for another process to release the lock.
+var LockTimeout = 10 * time.Second
+
+var (
+	mu   sync.Mutex
+	held = map[string]*heldLock{}
+)
+
+type heldLock struct {
+	f     *os.File
+	count int
+}
+
+// Lock takes an exclusive advisory lock on the file at path, creating it if
+// needed, and returns a function that releases it. Locks are re-entrant
+// within a process, so a command can hold the lock across a load-modify-save
+// while Save takes it again. Waits up to LockTimeout for other processes.
+func Lock(path string) (func(), error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if h, ok := held[path]; ok {
+		h.count++
+		return func() { release(path) }, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out waiting for lock %s (is another prj running?)", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	held[path] = &heldLock{f: f, count: 1}
+	return func() { release(path) }, nil
+}
+
+func release(path string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	h, ok := held[path]
+	if !ok {
+		return
+	}
+	h.count--
+	if h.count > 0 {
+		return
+	}
+	unlock(h.f)
+	h.f.Close()
+	delete(held, path)
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || linux || freebsd || netbsd || openbsd || dragonfly)
+
+package fsutil
+
+import "os"
+
+// Advisory locking isn't implemented on this platform; writes are still
+// atomic, but concurrent prj invocations aren't serialized.
+func tryLock(f *os.File) (bool, error) { return true, nil }
+
+func unlock(f *os.File) {}
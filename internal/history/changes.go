@@ -0,0 +1,182 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Change kinds.
+const (
+	ChangeNew          = "new"
+	ChangeRemoved      = "removed"
+	ChangeMoved        = "moved"
+	ChangeStatus       = "status"
+	ChangeTech         = "tech"
+	ChangeContributors = "contributors"
+	ChangeTodos        = "todos"
+)
+
+// Change is one entry in the change feed.
+type Change struct {
+	At     string `json:"at"`
+	Kind   string `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Path   string `json:"path"`
+	Name   string `json:"name"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func ChangesPath() string {
+	return filepath.Join(config.Dir(), "changes.jsonl")
+}
+
+// Diff compares the store before and after a scan. Projects are paired by
+// path, then by stable ID so a moved repo isn't reported as removed + new.
+// A project that became missing is reported as removed.
+func Diff(at string, before, after []*project.Project) []Change {
+	afterPaths := make(map[string]bool, len(after))
+	for _, p := range after {
+		afterPaths[p.Path] = true
+	}
+	byPath := make(map[string]*project.Project, len(before))
+	byID := make(map[string]*project.Project, len(before))
+	for _, p := range before {
+		byPath[p.Path] = p
+		if p.ID != "" && !afterPaths[p.Path] {
+			byID[p.ID] = p
+		}
+	}
+
+	var changes []Change
+	add := func(p *project.Project, kind, from, to, detail string) {
+		changes = append(changes, Change{
+			At: at, Kind: kind, ID: p.ID, Path: p.Path, Name: p.Name,
+			From: from, To: to, Detail: detail,
+		})
+	}
+
+	for _, p := range after {
+		old, ok := byPath[p.Path]
+		if !ok && p.ID != "" {
+			if old, ok = byID[p.ID]; ok {
+				add(p, ChangeMoved, old.Path, p.Path, "")
+			}
+		}
+		if !ok {
+			if p.Missing == "" {
+				add(p, ChangeNew, "", "", strings.Join(p.TechStack, ", "))
+			}
+			continue
+		}
+
+		if p.Missing != "" {
+			if old.Missing == "" {
+				add(p, ChangeRemoved, "", "", p.Missing)
+			}
+			continue
+		}
+		if old.Missing != "" {
+			add(p, ChangeNew, "", "", "found again")
+		}
+
+		if old.Status != p.Status {
+			add(p, ChangeStatus, old.Status, p.Status, "")
+		}
+		if added := newItems(old.TechStack, p.TechStack); len(added) > 0 {
+			add(p, ChangeTech, "", "", strings.Join(added, ", "))
+		}
+		if added := newItems(old.Contributors, p.Contributors); len(added) > 0 {
+			add(p, ChangeContributors, "", "", strings.Join(added, ", "))
+		}
+		if old.TodoOpen != p.TodoOpen || old.TodoClosed != p.TodoClosed {
+			add(p, ChangeTodos, "", "", fmt.Sprintf("open %d→%d, closed %d→%d",
+				old.TodoOpen, p.TodoOpen, old.TodoClosed, p.TodoClosed))
+		}
+	}
+	return changes
+}
+
+// LastScanPath is the file holding when "prj scan" last saved. Neither
+// log has a line for every scan, so "prj changes" can't tell from them
+// whether the latest scan changed anything.
+func LastScanPath() string {
+	return filepath.Join(config.Dir(), "last_scan")
+}
+
+// RecordScan notes that a scan saved at at (RFC 3339).
+func RecordScan(at string) error {
+	return fsutil.WriteFileAtomic(LastScanPath(), []byte(at+"\n"), 0644)
+}
+
+// LastScan returns when the last scan saved, or "" if no scan has been
+// recorded since prj started keeping track.
+func LastScan() (string, error) {
+	data, err := os.ReadFile(LastScanPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// AppendChanges adds changes to the end of the change log.
+func AppendChanges(changes []Change) error {
+	records := make([]any, len(changes))
+	for i, c := range changes {
+		records[i] = c
+	}
+	return appendLines(ChangesPath(), records...)
+}
+
+// LoadChanges reads changes recorded at or after since (RFC 3339; "" for
+// all), oldest first.
+func LoadChanges(since string) ([]Change, error) {
+	f, err := os.Open(ChangesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var changes []Change
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var c Change
+		if err := json.Unmarshal(s.Bytes(), &c); err != nil {
+			continue
+		}
+		if since != "" && c.At < since {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, s.Err()
+}
+
+func newItems(before, after []string) []string {
+	had := make(map[string]bool, len(before))
+	for _, s := range before {
+		had[s] = true
+	}
+	var added []string
+	for _, s := range after {
+		if !had[s] {
+			added = append(added, s)
+		}
+	}
+	return added
+}
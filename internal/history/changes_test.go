@@ -0,0 +1,84 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+// describe renders changes one per line as "kind path from>to detail".
+func describe(changes []Change) string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = fmt.Sprintf("%s %s %s>%s %s", c.Kind, c.Path, c.From, c.To, c.Detail)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiff(t *testing.T) {
+	api := func(edit func(p *project.Project)) *project.Project {
+		p := &project.Project{
+			ID: "id-api", Name: "api", Path: "/dev/api", Status: "active",
+			TechStack: []string{"go"}, Contributors: []string{"ann"}, TodoOpen: 2, TodoClosed: 1,
+		}
+		if edit != nil {
+			edit(p)
+		}
+		return p
+	}
+	list := func(ps ...*project.Project) []*project.Project { return ps }
+
+	tests := []struct {
+		name          string
+		before, after []*project.Project
+		want          string
+	}{
+		{"unchanged", list(api(nil)), list(api(nil)), ""},
+		{"status", list(api(nil)), list(api(func(p *project.Project) { p.Status = "paused" })),
+			"status /dev/api active>paused "},
+		{"new contributors only", list(api(nil)),
+			list(api(func(p *project.Project) { p.Contributors = []string{"bob", "ann", "cy"} })),
+			"contributors /dev/api > bob, cy"},
+		{"contributor left", list(api(nil)),
+			list(api(func(p *project.Project) { p.Contributors = nil })), ""},
+		{"tech", list(api(nil)),
+			list(api(func(p *project.Project) { p.TechStack = []string{"go", "docker"} })),
+			"tech /dev/api > docker"},
+		{"todos", list(api(nil)), list(api(func(p *project.Project) { p.TodoOpen, p.TodoClosed = 1, 2 })),
+			"todos /dev/api > open 2→1, closed 1→2"},
+		{"added", nil, list(api(nil)), "new /dev/api > go"},
+		{"added missing", nil, list(api(func(p *project.Project) { p.Missing = "path not found" })), ""},
+		{"removed", list(api(nil)), list(api(func(p *project.Project) { p.Missing = "path not found" })),
+			"removed /dev/api > path not found"},
+		{"still missing",
+			list(api(func(p *project.Project) { p.Missing = "path not found" })),
+			list(api(func(p *project.Project) { p.Missing = "path not found"; p.Status = "paused" })), ""},
+		{"found again",
+			list(api(func(p *project.Project) { p.Missing = "path not found" })), list(api(nil)),
+			"new /dev/api > found again"},
+		{"moved", list(api(nil)), list(api(func(p *project.Project) { p.Path = "/archive/api" })),
+			"moved /archive/api /dev/api>/archive/api "},
+		{"moved and changed", list(api(nil)),
+			list(api(func(p *project.Project) { p.Path, p.Status = "/archive/api", "paused" })),
+			"moved /archive/api /dev/api>/archive/api \nstatus /archive/api active>paused "},
+		// A clone at a new path while the original is still there isn't a move.
+		{"clone", list(api(nil)), list(api(nil), api(func(p *project.Project) { p.Path = "/tmp/api" })),
+			"new /tmp/api > go"},
+		{"no ID, new path", list(api(func(p *project.Project) { p.ID = "" })),
+			list(api(func(p *project.Project) { p.ID, p.Path = "", "/archive/api" })),
+			"new /archive/api > go"},
+	}
+	for _, tt := range tests {
+		got := describe(Diff("2026-10-01T00:00:00Z", tt.before, tt.after))
+		if got != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	changes := Diff("2026-10-01T00:00:00Z", list(api(nil)), list(api(func(p *project.Project) { p.Status = "wip" })))
+	if c := changes[0]; c.At != "2026-10-01T00:00:00Z" || c.ID != "id-api" || c.Name != "api" {
+		t.Errorf("change = %+v, want the scan time, ID and name filled in", c)
+	}
+}
@@ -0,0 +1,275 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Snapshot is the compact per-project record kept for every scan.
+type Snapshot struct {
+	ID            string   `json:"id,omitempty"`
+	Path          string   `json:"path"`
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	CommitCount8M int      `json:"commit_count_8m"`
+	TodoOpen      int      `json:"todo_open"`
+	TodoClosed    int      `json:"todo_closed"`
+	TechStack     []string `json:"tech_stack,omitempty"`
+}
+
+// Scan is one line of history.jsonl: every project as of one scan.
+type Scan struct {
+	At       string     `json:"at"`
+	Projects []Snapshot `json:"projects"`
+}
+
+func Path() string {
+	return filepath.Join(config.Dir(), "history.jsonl")
+}
+
+// FromProjects builds a scan record, leaving out projects marked missing.
+func FromProjects(at string, projects []*project.Project) Scan {
+	s := Scan{At: at}
+	for _, p := range projects {
+		if p.Missing != "" {
+			continue
+		}
+		s.Projects = append(s.Projects, Snapshot{
+			ID:            p.ID,
+			Path:          p.Path,
+			Name:          p.Name,
+			Status:        p.EffectiveStatus(),
+			CommitCount8M: p.CommitCount8M,
+			TodoOpen:      p.TodoOpen,
+			TodoClosed:    p.TodoClosed,
+			TechStack:     p.TechStack,
+		})
+	}
+	return s
+}
+
+// Append adds a scan to the end of the history file, unless every project
+// is as it was in the last recorded scan. It reports whether it wrote.
+func Append(s Scan) (bool, error) {
+	last, err := lastScan(Path())
+	if err != nil {
+		return false, err
+	}
+	if last != nil && sameProjects(last.Projects, s.Projects) {
+		return false, nil
+	}
+	return true, appendLines(Path(), s)
+}
+
+// lastScan reads the last line of the history file, or nil if there is
+// none. It reads backwards from the end rather than through the file.
+func lastScan(path string) (*Scan, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunk = 64 * 1024
+	end := info.Size()
+	var line []byte
+	for off := end; off > 0; {
+		n := int64(chunk)
+		if n > off {
+			n = off
+		}
+		off -= n
+		buf := make([]byte, n)
+		if _, err := f.ReadAt(buf, off); err != nil {
+			return nil, err
+		}
+		line = append(buf, line...)
+		trimmed := bytes.TrimRight(line, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			line = trimmed[i+1:]
+			break
+		}
+		if off == 0 {
+			line = trimmed
+		}
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+	var s Scan
+	if err := json.Unmarshal(line, &s); err != nil {
+		// A write cut short; record the next scan in full.
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// sameProjects compares snapshots by their encoding, so an empty and a
+// missing tech stack are equal just as they are once written.
+func sameProjects(a, b []Snapshot) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// Trim drops scans recorded before cutoff from the history file, always
+// keeping the newest one so the next scan has something to compare with.
+// It returns how many scans were dropped; the file is only rewritten when
+// there is something to drop.
+func Trim(cutoff time.Time) (int, error) {
+	unlock, err := config.Lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	// Most scans have nothing to drop: check the oldest before reading it all.
+	if first, err := firstScanAt(Path()); err != nil || first.IsZero() || !first.Before(cutoff) {
+		return 0, err
+	}
+	scans, err := Load()
+	if err != nil || len(scans) == 0 {
+		return 0, err
+	}
+	drop := 0
+	for drop < len(scans)-1 {
+		t, err := time.Parse(time.RFC3339, scans[drop].At)
+		if err != nil || !t.Before(cutoff) {
+			break
+		}
+		drop++
+	}
+	if drop == 0 {
+		return 0, nil
+	}
+
+	var buf []byte
+	for _, s := range scans[drop:] {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return 0, err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	return drop, fsutil.WriteFileAtomic(Path(), buf, 0644)
+}
+
+// appendLines writes each record as one JSON line at the end of path, in a
+// single write so concurrent readers never see half a batch.
+func appendLines(path string, records ...any) error {
+	if len(records) == 0 {
+		return nil
+	}
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var buf []byte
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// firstScanAt returns when the oldest recorded scan happened, or the zero
+// time if there is none or it can't be read.
+func firstScanAt(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	defer f.Close()
+	line, _ := bufio.NewReader(f).ReadBytes('\n')
+	var s struct {
+		At string `json:"at"`
+	}
+	if json.Unmarshal(line, &s) != nil {
+		return time.Time{}, nil
+	}
+	t, _ := time.Parse(time.RFC3339, s.At)
+	return t, nil
+}
+
+// Load reads every recorded scan, oldest first. Lines that fail to parse
+// (e.g. a write cut short) are skipped.
+func Load() ([]Scan, error) {
+	f, err := os.Open(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var scans []Scan
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for s.Scan() {
+		var scan Scan
+		if err := json.Unmarshal(s.Bytes(), &scan); err != nil {
+			continue
+		}
+		scans = append(scans, scan)
+	}
+	return scans, s.Err()
+}
+
+// Point is one project's snapshot at the time of a scan.
+type Point struct {
+	At string
+	Snapshot
+}
+
+// ForProject returns the project's snapshots across all scans, matching by
+// stable ID when it has one so history follows moved repos.
+func ForProject(scans []Scan, p *project.Project) []Point {
+	var points []Point
+	for _, scan := range scans {
+		for _, s := range scan.Projects {
+			if s.Path == p.Path || (p.ID != "" && s.ID == p.ID) {
+				points = append(points, Point{At: scan.At, Snapshot: s})
+				break
+			}
+		}
+	}
+	return points
+}
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peeomid/prj/internal/config"
+)
+
+func tempHome(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func scanAt(at time.Time, status string) Scan {
+	return Scan{At: at.UTC().Format(time.RFC3339), Projects: []Snapshot{
+		{Path: "/dev/api", Name: "api", Status: status, CommitCount8M: 3},
+		{Path: "/dev/web", Name: "web", Status: "paused", TechStack: []string{}},
+	}}
+}
+
+func TestAppendSkipsUnchanged(t *testing.T) {
+	tempHome(t)
+	now := time.Now()
+
+	steps := []struct {
+		scan  Scan
+		wrote bool
+	}{
+		{scanAt(now.Add(-3*time.Hour), "active"), true},
+		{scanAt(now.Add(-2*time.Hour), "active"), false},
+		{scanAt(now.Add(-time.Hour), "paused"), true},
+		{scanAt(now, "paused"), false},
+	}
+	for i, s := range steps {
+		wrote, err := Append(s.scan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wrote != s.wrote {
+			t.Errorf("Append #%d wrote = %v, want %v", i+1, wrote, s.wrote)
+		}
+	}
+	scans, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scans) != 2 || scans[1].Projects[0].Status != "paused" {
+		t.Errorf("history has %d scans, want the 2 that changed something", len(scans))
+	}
+}
+
+func TestLastScanLongLines(t *testing.T) {
+	tempHome(t)
+	// Lines longer than the read chunk.
+	big := strings.Repeat("x", 200*1024)
+	first := Scan{At: "2026-01-01T00:00:00Z", Projects: []Snapshot{{Path: "/a", Name: big}}}
+	second := Scan{At: "2026-01-02T00:00:00Z", Projects: []Snapshot{{Path: "/b", Name: big}}}
+	if err := appendLines(Path(), first, second); err != nil {
+		t.Fatal(err)
+	}
+	last, err := lastScan(Path())
+	if err != nil || last == nil || last.At != second.At {
+		t.Fatalf("lastScan = %v, %v; want the second scan", last, err)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	tempHome(t)
+	now := time.Now()
+	for i, days := range []int{400, 300, 10, 1} {
+		if _, err := Append(scanAt(now.AddDate(0, 0, -days), []string{"a", "b", "c", "d"}[i])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dropped, err := Trim(now.AddDate(0, 0, -365))
+	if err != nil || dropped != 1 {
+		t.Fatalf("Trim = %d, %v; want 1 dropped", dropped, err)
+	}
+	scans, _ := Load()
+	if len(scans) != 3 || scans[0].Projects[0].Status != "b" {
+		t.Errorf("after Trim: %d scans starting with %q", len(scans), scans[0].Projects[0].Status)
+	}
+
+	if dropped, err := Trim(now.AddDate(0, 0, -365)); err != nil || dropped != 0 {
+		t.Errorf("second Trim = %d, %v; want nothing to drop", dropped, err)
+	}
+
+	// The newest scan is always kept.
+	if dropped, err := Trim(now.Add(time.Hour)); err != nil || dropped != 2 {
+		t.Errorf("Trim of everything = %d, %v; want 2 dropped", dropped, err)
+	}
+	if scans, _ := Load(); len(scans) != 1 || scans[0].Projects[0].Status != "d" {
+		t.Errorf("after trimming everything: %v", scans)
+	}
+}
+
+func TestTrendsCarryForward(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, -2, 0)
+	trends, err := Trends([]Scan{scanAt(old, "active")}, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trends) < 3 {
+		t.Fatalf("got %d months, want one per month up to now", len(trends))
+	}
+	if trends[0].Scans != 1 || trends[0].Period != old.Format("2006-01") {
+		t.Errorf("first month = %+v", trends[0])
+	}
+	lastMonth := trends[len(trends)-1]
+	if lastMonth.Period != now.Format("2006-01") || lastMonth.Scans != 0 || lastMonth.Status["active"] != 1 {
+		t.Errorf("current month = %+v, want the old scan carried forward", lastMonth)
+	}
+}
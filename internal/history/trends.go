@@ -0,0 +1,111 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Trend summarizes the fleet as of the last scan in a period.
+type Trend struct {
+	Period     string
+	Scans      int
+	Projects   int
+	Status     map[string]int
+	Commits8M  int
+	TodoOpen   int
+	TodoClosed int
+	Tech       map[string]int
+}
+
+// Trends groups scans by period ("week", "month" or "quarter") and reports
+// each period using its last scan, oldest period first. Scans that changed
+// nothing aren't recorded, so a period without any repeats the one before
+// it, up to the current period.
+func Trends(scans []Scan, by string) ([]Trend, error) {
+	key, err := periodFunc(by)
+	if err != nil {
+		return nil, err
+	}
+
+	byPeriod := map[string]*Trend{}
+	last := map[string]string{}
+	var first time.Time
+	for _, scan := range scans {
+		t, err := time.Parse(time.RFC3339, scan.At)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		period := key(t.Local())
+		tr, ok := byPeriod[period]
+		if !ok {
+			tr = &Trend{Period: period}
+			byPeriod[period] = tr
+		}
+		tr.Scans++
+		if scan.At < last[period] {
+			continue
+		}
+		last[period] = scan.At
+		fillTrend(tr, scan)
+	}
+
+	if !first.IsZero() {
+		var prev *Trend
+		for d := first.Local(); !d.After(time.Now()); d = d.AddDate(0, 0, 1) {
+			period := key(d)
+			tr, ok := byPeriod[period]
+			if !ok && prev != nil {
+				carried := *prev
+				carried.Period, carried.Scans = period, 0
+				tr = &carried
+				byPeriod[period] = tr
+			}
+			prev = tr
+		}
+	}
+
+	trends := make([]Trend, 0, len(byPeriod))
+	for _, tr := range byPeriod {
+		trends = append(trends, *tr)
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Period < trends[j].Period })
+	return trends, nil
+}
+
+func fillTrend(tr *Trend, scan Scan) {
+	tr.Projects = len(scan.Projects)
+	tr.Status = map[string]int{}
+	tr.Tech = map[string]int{}
+	tr.Commits8M, tr.TodoOpen, tr.TodoClosed = 0, 0, 0
+	for _, s := range scan.Projects {
+		tr.Status[s.Status]++
+		tr.Commits8M += s.CommitCount8M
+		tr.TodoOpen += s.TodoOpen
+		tr.TodoClosed += s.TodoClosed
+		for _, t := range s.TechStack {
+			tr.Tech[t]++
+		}
+	}
+}
+
+func periodFunc(by string) (func(time.Time) string, error) {
+	switch by {
+	case "week":
+		return func(t time.Time) string {
+			y, w := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", y, w)
+		}, nil
+	case "month", "":
+		return func(t time.Time) string { return t.Format("2006-01") }, nil
+	case "quarter":
+		return func(t time.Time) string {
+			return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown period %q (want week, month or quarter)", by)
+	}
+}
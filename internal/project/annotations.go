@@ -0,0 +1,64 @@
+package project
+
+import "strings"
+
+// HasTag reports whether p carries tag (case-insensitive).
+func (p *Project) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds the tags p doesn't already have, keeping the list sorted,
+// and returns the ones that were new.
+func (p *Project) AddTags(tags ...string) []string {
+	var added []string
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || p.HasTag(t) {
+			continue
+		}
+		p.Tags = append(p.Tags, t)
+		added = append(added, t)
+	}
+	sortFold(p.Tags)
+	return added
+}
+
+// RemoveTags drops the given tags from p and returns the ones it had.
+func (p *Project) RemoveTags(tags ...string) []string {
+	var removed []string
+	kept := p.Tags[:0]
+	for _, t := range p.Tags {
+		drop := false
+		for _, r := range tags {
+			if strings.EqualFold(t, strings.TrimSpace(r)) {
+				drop = true
+				break
+			}
+		}
+		if drop {
+			removed = append(removed, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	p.Tags = kept
+	if len(p.Tags) == 0 {
+		p.Tags = nil
+	}
+	return removed
+}
+
+// sortFold sorts strings case-insensitively (insertion sort; tag lists are
+// short).
+func sortFold(s []string) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && strings.ToLower(s[j]) < strings.ToLower(s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/expr"
+)
+
+// Classifier assigns a project's status: first the built-in ladder from
+// InferState, using the thresholds configured for the repo's folder, then
+// the first user-defined status rule that matches. Inside a rule, "status"
+// is the built-in status (or the mark, for archived/abandoned/idea).
+type Classifier struct {
+	cfg   *config.Config
+	rules []statusRule
+}
+
+type statusRule struct {
+	status string
+	when   *expr.Expr
+}
+
+// NewClassifier compiles the status rules in cfg. A nil cfg uses the
+// defaults and no rules.
+func NewClassifier(cfg *config.Config) (*Classifier, error) {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	c := &Classifier{cfg: cfg}
+	for i, r := range cfg.StatusRules {
+		if r.Status == "" {
+			return nil, fmt.Errorf("status rule %d: missing status", i+1)
+		}
+		when, err := expr.Compile(r.When, Fields)
+		if err != nil {
+			return nil, fmt.Errorf("status rule %d (%s): %w", i+1, r.Status, err)
+		}
+		c.rules = append(c.rules, statusRule{status: r.Status, when: when})
+	}
+	return c, nil
+}
+
+// Classify sets p's Status and TODO counts.
+func (c *Classifier) Classify(repoPath string, p *Project) {
+	p.Status, p.TodoOpen, p.TodoClosed = InferState(repoPath, p.LastCommitDate, p.RecentCommits, c.cfg.ThresholdsFor(repoPath))
+	for _, r := range c.rules {
+		if r.when.Eval(p) {
+			p.Status = r.status
+			return
+		}
+	}
+}
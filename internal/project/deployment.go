@@ -6,30 +6,31 @@ import (
 	"path/filepath"
 )
 
+// deploymentChecks maps files and directories to deployment labels.
+var deploymentChecks = []struct {
+	path  string
+	label string
+}{
+	{"Dockerfile", "docker"},
+	{"docker-compose.yml", "docker-compose"},
+	{"docker-compose.yaml", "docker-compose"},
+	{"Procfile", "heroku"},
+	{"fly.toml", "fly.io"},
+	{"vercel.json", "vercel"},
+	{"netlify.toml", "netlify"},
+	{"app.yaml", "gcp"},
+	{"serverless.yml", "serverless"},
+	{"serverless.yaml", "serverless"},
+	{filepath.Join("bin", "deploy"), "deploy-script"},
+	{".github/workflows", "github-actions"},
+	{".circleci", "circleci"},
+}
+
 // DetectDeployment checks for deployment-related files and configs.
 func DetectDeployment(dir string) []string {
 	var result []string
 
-	checks := []struct {
-		path  string
-		label string
-	}{
-		{"Dockerfile", "docker"},
-		{"docker-compose.yml", "docker-compose"},
-		{"docker-compose.yaml", "docker-compose"},
-		{"Procfile", "heroku"},
-		{"fly.toml", "fly.io"},
-		{"vercel.json", "vercel"},
-		{"netlify.toml", "netlify"},
-		{"app.yaml", "gcp"},
-		{"serverless.yml", "serverless"},
-		{"serverless.yaml", "serverless"},
-		{filepath.Join("bin", "deploy"), "deploy-script"},
-		{".github/workflows", "github-actions"},
-		{".circleci", "circleci"},
-	}
-
-	for _, c := range checks {
+	for _, c := range deploymentChecks {
 		if _, err := os.Stat(filepath.Join(dir, c.path)); err == nil {
 			result = append(result, c.label)
 		}
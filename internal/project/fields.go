@@ -0,0 +1,130 @@
+package project
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/peeomid/prj/internal/expr"
+)
+
+// Fields are the names usable in status rules and list queries, with the
+// kind of value each holds. Some have a short alias next to the JSON name.
+var Fields = map[string]expr.Kind{
+	"name":                expr.KindString,
+	"path":                expr.KindString,
+	"folder":              expr.KindString,
+	"description":         expr.KindString,
+	"type":                expr.KindString,
+	"inferred_type":       expr.KindString,
+	"status":              expr.KindString,
+	"mark":                expr.KindString,
+	"note":                expr.KindString,
+	"remote":              expr.KindString,
+	"git_remote":          expr.KindString,
+	"remote_host":         expr.KindString,
+	"last_commit_message": expr.KindString,
+	"last_commit_author":  expr.KindString,
+	"tags":                expr.KindList,
+	"tech":                expr.KindList,
+	"tech_stack":          expr.KindList,
+	"deployment":          expr.KindList,
+	"contributors":        expr.KindList,
+	"recent_messages":     expr.KindList,
+	"fork":                expr.KindBool,
+	"is_fork":             expr.KindBool,
+	"missing":             expr.KindBool,
+	"dirty":               expr.KindBool,
+	"unpushed":            expr.KindNumber,
+	"commits":             expr.KindNumber,
+	"commit_count_8m":     expr.KindNumber,
+	"todo_open":           expr.KindNumber,
+	"todo_closed":         expr.KindNumber,
+	"days_since_commit":   expr.KindNumber,
+	"plans_count":         expr.KindNumber,
+	"ai_docs_count":       expr.KindNumber,
+	"last_commit":         expr.KindDate,
+	"last_commit_date":    expr.KindDate,
+	"scanned_at":          expr.KindDate,
+}
+
+// Lookup implements expr.Env.
+func (p *Project) Lookup(name string) any {
+	switch name {
+	case "name":
+		return p.Name
+	case "path":
+		return p.Path
+	case "folder":
+		return filepath.Dir(p.Path)
+	case "description":
+		return p.Description
+	case "type", "inferred_type":
+		return p.InferredType
+	case "status":
+		return p.EffectiveStatus()
+	case "mark":
+		return p.Mark
+	case "note":
+		return p.Note
+	case "tags":
+		return p.Tags
+	case "remote", "git_remote":
+		return p.GitRemote
+	case "remote_host":
+		return RemoteHost(p.GitRemote)
+	case "last_commit_message":
+		return p.LastCommitMessage
+	case "last_commit_author":
+		return p.LastCommitAuthor
+	case "tech", "tech_stack":
+		return p.TechStack
+	case "deployment":
+		return p.Deployment
+	case "contributors":
+		return p.Contributors
+	case "recent_messages":
+		msgs := make([]string, len(p.RecentCommits))
+		for i, c := range p.RecentCommits {
+			msgs[i] = c.Message
+		}
+		return msgs
+	case "fork", "is_fork":
+		return p.IsFork
+	case "missing":
+		return p.Missing != ""
+	case "dirty":
+		return p.Dirty
+	case "unpushed":
+		return float64(p.Unpushed)
+	case "commits", "commit_count_8m":
+		return float64(p.CommitCount8M)
+	case "todo_open":
+		return float64(p.TodoOpen)
+	case "todo_closed":
+		return float64(p.TodoClosed)
+	case "days_since_commit":
+		t := parseTime(p.LastCommitDate)
+		if t.IsZero() {
+			// Never committed: older than anything.
+			return float64(1 << 30)
+		}
+		return float64(int(time.Since(t).Hours() / 24))
+	case "plans_count":
+		return float64(p.PlansCount)
+	case "ai_docs_count":
+		return float64(p.AIDocsCount)
+	case "last_commit", "last_commit_date":
+		return parseTime(p.LastCommitDate)
+	case "scanned_at":
+		return parseTime(p.ScannedAt)
+	}
+	return nil
+}
+
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peeomid/prj/internal/scanner"
+)
+
+// fingerprintVersion is mixed into every fingerprint. Bump it whenever
+// extraction changes so stored projects are re-extracted on the next scan.
+const fingerprintVersion = "3"
+
+// fingerprintFiles are the files (relative to the repo root) read by
+// DetectTechStack, ExtractDescription and InferState. Reference files and
+// deployment markers are added from their own lists.
+var fingerprintFiles = []string{
+	"Gemfile", "package.json", "requirements.txt", "pyproject.toml",
+	"setup.py", "go.mod", "Package.swift", "Cargo.toml",
+	filepath.Join(".ai", "PROJECT_STATUS.md"),
+}
+
+// fingerprintDirs are directories whose listing feeds extraction. A
+// directory's mtime changes when entries are added, removed or renamed.
+var fingerprintDirs = []string{
+	".", ".ai", ".cursor", "docs", "tasks",
+}
+
+// Fingerprint returns a cheap hash of everything extraction depends on:
+// the HEAD ref, the git index and config, and the mtimes of the files and
+// directories that tech stack, description, reference, state and
+// deployment detection read. It never runs more than one git process.
+func Fingerprint(repoPath string) string {
+	var b strings.Builder
+	b.WriteString("v" + fingerprintVersion + "\n")
+	b.WriteString("head " + scanner.HeadRef(repoPath) + "\n")
+
+	stamp := func(rel string) {
+		info, err := os.Stat(filepath.Join(repoPath, rel))
+		if err != nil {
+			fmt.Fprintf(&b, "%s -\n", rel)
+			return
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", rel, info.ModTime().UnixNano(), info.Size())
+	}
+
+	stamp(filepath.Join(".git", "index"))
+	stamp(filepath.Join(".git", "config"))
+	for _, rel := range fingerprintFiles {
+		stamp(rel)
+	}
+	for _, rel := range rootReferenceFiles {
+		stamp(rel)
+	}
+	for _, c := range deploymentChecks {
+		stamp(c.path)
+	}
+	for _, rel := range fingerprintDirs {
+		stamp(rel)
+	}
+	for _, nested := range findNestedRepos(repoPath) {
+		b.WriteString("nested " + nested + "\n")
+	}
+
+	sum := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// Refresh reuses prev when the repo's fingerprint still matches it and
+// re-extracts otherwise. The boolean reports whether prev was reused.
+// Reused projects get their status re-inferred, since it depends on today's
+// date and the configured thresholds and rules as well as on the repo, and
+// their working state and 8-month commit count re-read: edits, pushes and
+// the window moving on don't change the fingerprint. ScannedAt stays that
+// of the extraction prev came from.
+func Refresh(repoPath string, prev *Project, c *Classifier) (*Project, bool) {
+	if prev == nil || prev.Fingerprint == "" || Fingerprint(repoPath) != prev.Fingerprint {
+		return Extract(repoPath, prev, c), false
+	}
+
+	p := *prev
+	p.Dirty, p.Unpushed = scanner.WorkingState(repoPath)
+	p.CommitCount8M = scanner.CommitCountSince(repoPath, "8 months ago")
+	c.Classify(repoPath, &p)
+	return &p, true
+}
+
+// Extract re-extracts the repo, carries over prev's annotations when prev
+// is non-nil, and then classifies it, so status rules see the annotations.
+func Extract(repoPath string, prev *Project, c *Classifier) *Project {
+	p := ExtractFromPath(repoPath)
+	if prev != nil {
+		p.Inherit(prev)
+	}
+	c.Classify(repoPath, p)
+	return p
+}
@@ -0,0 +1,238 @@
+package project
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/peeomid/prj/internal/config"
+)
+
+// git runs git in dir with a fixed identity, failing the test on error.
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL="+os.DevNull,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// gitRepo creates a repo with one commit under a fresh temp directory.
+func gitRepo(t *testing.T, name string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := filepath.Join(t.TempDir(), name)
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "init", "-q")
+	writeFile(t, repo, "README.md", "# "+name+"\n")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-q", "-m", "initial")
+	return repo
+}
+
+// stamps counts writeFile calls so each write gets a distinct mtime.
+var stamps int
+
+// writeFile writes rel under repo, giving it and its directory a distinct
+// mtime in the past. Past, because git rewrites an index older than the
+// files it tracks on the next status, which would change the fingerprint.
+func writeFile(t *testing.T, repo, rel, data string) {
+	t.Helper()
+	path := filepath.Join(repo, rel)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stamps++
+	at := time.Now().Add(-time.Hour + time.Duration(stamps)*time.Second)
+	for _, p := range []string{path, filepath.Dir(path)} {
+		if err := os.Chtimes(p, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func annotated(p *Project) *Project {
+	p.Mark = "focus"
+	p.Tags = []string{"acme"}
+	p.Note = "ship it"
+	return p
+}
+
+func checkAnnotations(t *testing.T, what string, p *Project) {
+	t.Helper()
+	if p.Mark != "focus" || len(p.Tags) != 1 || p.Tags[0] != "acme" || p.Note != "ship it" {
+		t.Errorf("%s lost the annotations: mark %q, tags %v, note %q", what, p.Mark, p.Tags, p.Note)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	repo := gitRepo(t, "api")
+	fp := Fingerprint(repo)
+	if fp == "" || Fingerprint(repo) != fp {
+		t.Fatal("Fingerprint isn't stable for an unchanged repo")
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+	}{
+		{"a detected file", func() { writeFile(t, repo, "go.mod", "module api\n") }},
+		{"a new commit", func() {
+			git(t, repo, "add", ".")
+			git(t, repo, "commit", "-q", "-m", "add go.mod")
+		}},
+		{"the index", func() {
+			writeFile(t, repo, "main.go", "package main\n")
+			git(t, repo, "add", "main.go")
+		}},
+	}
+	for _, tt := range tests {
+		tt.change()
+		got := Fingerprint(repo)
+		if got == fp {
+			t.Errorf("changing %s kept the fingerprint", tt.name)
+		}
+		fp = got
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	repo := gitRepo(t, "api")
+	c, err := NewClassifier(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, reused := Refresh(repo, nil, c)
+	if reused || p.Fingerprint == "" || p.Status == "" {
+		t.Fatalf("Refresh without prev = reused %v, fingerprint %q, status %q", reused, p.Fingerprint, p.Status)
+	}
+
+	// Unchanged: prev is reused, with the rolling counts re-read.
+	prev := annotated(p)
+	prev.Description = "kept from prev"
+	prev.CommitCount8M = 99
+	prev.Status = "stale"
+	got, reused := Refresh(repo, prev, c)
+	if !reused {
+		t.Fatal("an unchanged repo was re-extracted")
+	}
+	if got == prev {
+		t.Error("Refresh returned prev itself instead of a copy")
+	}
+	if got.Description != "kept from prev" || got.ScannedAt != prev.ScannedAt {
+		t.Errorf("reused project = description %q, scanned_at %q; want prev's", got.Description, got.ScannedAt)
+	}
+	if got.CommitCount8M != 1 {
+		t.Errorf("reused CommitCount8M = %d, want 1 (re-counted)", got.CommitCount8M)
+	}
+	if got.Status == "stale" {
+		t.Error("reused project wasn't reclassified")
+	}
+	checkAnnotations(t, "a reused project", got)
+
+	// Changed: re-extracted, annotations carried over.
+	writeFile(t, repo, "go.mod", "module api\n")
+	got, reused = Refresh(repo, prev, c)
+	if reused {
+		t.Fatal("a changed repo was reused")
+	}
+	if got.Description == "kept from prev" || got.Fingerprint == prev.Fingerprint {
+		t.Errorf("changed repo kept prev's data: description %q", got.Description)
+	}
+	if len(got.TechStack) == 0 || got.TechStack[0] != "go" {
+		t.Errorf("re-extracted TechStack = %v, want go", got.TechStack)
+	}
+	checkAnnotations(t, "a re-extracted project", got)
+}
+
+func TestExtractAllFull(t *testing.T) {
+	repo := gitRepo(t, "api")
+	c, err := NewClassifier(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := Refresh(repo, nil, c)
+	prev := annotated(p)
+	prev.Description = "kept from prev"
+	byPath := map[string]*Project{repo: prev}
+
+	var reusedCount int
+	progress := func(done int, path string, reused bool) {
+		if reused {
+			reusedCount++
+		}
+	}
+	got := ExtractAll([]string{repo}, 1, c, byPath, false, progress)
+	if reusedCount != 1 || got[0].Description != "kept from prev" {
+		t.Errorf("incremental ExtractAll: %d reused, description %q", reusedCount, got[0].Description)
+	}
+
+	reusedCount = 0
+	got = ExtractAll([]string{repo}, 1, c, byPath, true, progress)
+	if reusedCount != 0 || got[0].Description == "kept from prev" {
+		t.Errorf("full ExtractAll: %d reused, description %q; want a fresh extraction", reusedCount, got[0].Description)
+	}
+	checkAnnotations(t, "a full re-extraction", got[0])
+}
+
+func TestStatusRuleOnAnnotations(t *testing.T) {
+	repo := gitRepo(t, "api")
+	cfg := config.DefaultConfig()
+	cfg.StatusRules = []config.StatusRule{{Status: "client", When: "tags == foo"}}
+	c, err := NewClassifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := Refresh(repo, nil, c)
+	if p.Status == "client" {
+		t.Fatal("the rule matched a project without the tag")
+	}
+	p.Tags = []string{"foo"}
+	c.Classify(p.Path, p)
+	if p.Status != "client" {
+		t.Fatalf("Status = %q, want client", p.Status)
+	}
+
+	// Reused, re-extracted and fully re-extracted projects all see the tag.
+	if got, _ := Refresh(repo, p, c); got.Status != "client" {
+		t.Errorf("reused: Status = %q, want client", got.Status)
+	}
+	writeFile(t, repo, "go.mod", "module api\n")
+	if got, reused := Refresh(repo, p, c); reused || got.Status != "client" {
+		t.Errorf("re-extracted (reused %v): Status = %q, want client", reused, got.Status)
+	}
+	if got := ExtractAll([]string{repo}, 1, c, map[string]*Project{repo: p}, true, nil); got[0].Status != "client" {
+		t.Errorf("full scan: Status = %q, want client", got[0].Status)
+	}
+
+	// A moved repo is found at a path with no stored project, so it's
+	// extracted bare and only inherits the tag when the store matches it by
+	// ID; "prj scan" classifies it again after that.
+	moved := filepath.Join(t.TempDir(), "api")
+	if err := os.Rename(repo, moved); err != nil {
+		t.Fatal(err)
+	}
+	got := Extract(moved, nil, c)
+	if got.ID == "" || got.ID != p.ID {
+		t.Fatalf("moved repo ID = %q, want %q", got.ID, p.ID)
+	}
+	got.Inherit(p)
+	c.Classify(got.Path, got)
+	if got.Status != "client" {
+		t.Errorf("moved: Status = %q, want client", got.Status)
+	}
+}
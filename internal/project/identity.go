@@ -0,0 +1,53 @@
+package project
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// PathMove records a project being found at a new path.
+type PathMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	At   string `json:"at"`
+}
+
+// StableID derives an identity for a repo that survives moves and renames:
+// a hash of its root commit and normalized origin remote. Repos without any
+// commits have no stable identity and return "".
+func StableID(rootCommit, remote string) string {
+	if rootCommit == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(rootCommit + "\n" + NormalizeRemote(remote)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// NormalizeRemote reduces a remote URL to "host/owner/repo" so the SSH and
+// HTTPS forms of the same remote compare equal.
+func NormalizeRemote(remote string) string {
+	r := strings.TrimSpace(strings.ToLower(remote))
+	if r == "" {
+		return ""
+	}
+	if i := strings.Index(r, "://"); i >= 0 {
+		r = r[i+3:]
+	} else if i := strings.Index(r, ":"); i >= 0 {
+		// scp-like SSH: git@host:owner/repo
+		r = r[:i] + "/" + r[i+1:]
+	}
+	if i := strings.Index(r, "@"); i >= 0 && i < strings.Index(r+"/", "/") {
+		r = r[i+1:]
+	}
+	r = strings.TrimSuffix(strings.TrimSuffix(r, "/"), ".git")
+	return r
+}
+
+// RemoteHost is the host part of a remote URL ("github.com"), without a
+// port.
+func RemoteHost(remote string) string {
+	host, _, _ := strings.Cut(NormalizeRemote(remote), "/")
+	host, _, _ = strings.Cut(host, ":")
+	return host
+}
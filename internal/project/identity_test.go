@@ -0,0 +1,84 @@
+package project
+
+import "testing"
+
+func TestStableID(t *testing.T) {
+	ssh := StableID("abc123", "git@github.com:u/api.git")
+	if ssh == "" || len(ssh) != 16 {
+		t.Fatalf("StableID = %q, want 16 hex digits", ssh)
+	}
+	for _, remote := range []string{
+		"https://github.com/u/api.git",
+		"https://github.com/u/api",
+		"ssh://git@github.com/u/api.git",
+		"HTTPS://GitHub.com/u/api/",
+	} {
+		if id := StableID("abc123", remote); id != ssh {
+			t.Errorf("StableID with %q = %q, want %q (same as SSH form)", remote, id, ssh)
+		}
+	}
+	if id := StableID("abc123", "git@github.com:u/other.git"); id == ssh {
+		t.Error("a different remote gave the same ID")
+	}
+	if id := StableID("def456", "git@github.com:u/api.git"); id == ssh {
+		t.Error("a different root commit gave the same ID")
+	}
+	if id := StableID("abc123", ""); id == "" {
+		t.Error("a repo without a remote has no ID")
+	}
+	if id := StableID("", "git@github.com:u/api.git"); id != "" {
+		t.Errorf("a repo without commits got ID %q", id)
+	}
+}
+
+func TestNormalizeRemote(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:u/api.git":      "github.com/u/api",
+		"https://github.com/u/api.git":  "github.com/u/api",
+		"https://user@gitlab.com/g/s/r": "gitlab.com/g/s/r",
+		"ssh://git@host:2222/u/api.git": "host:2222/u/api",
+		"":                              "",
+	}
+	for in, want := range tests {
+		if got := NormalizeRemote(in); got != want {
+			t.Errorf("NormalizeRemote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInherit(t *testing.T) {
+	old := &Project{
+		Path:          "/old/api",
+		Status:        "paused",
+		Description:   "old description",
+		Mark:          "focus",
+		MarkedAt:      "2026-01-01T00:00:00Z",
+		Tags:          []string{"acme"},
+		Note:          "ship it",
+		NoteUpdatedAt: "2026-01-02T00:00:00Z",
+		Moves:         []PathMove{{From: "/older/api", To: "/old/api", At: "2025-12-01T00:00:00Z"}},
+	}
+	p := &Project{Path: "/new/api", Status: "active", Description: "new description"}
+	p.Inherit(old)
+
+	if p.Mark != "focus" || p.MarkedAt != old.MarkedAt || p.Note != "ship it" || p.NoteUpdatedAt != old.NoteUpdatedAt {
+		t.Errorf("annotations not inherited: %+v", p)
+	}
+	if len(p.Tags) != 1 || p.Tags[0] != "acme" {
+		t.Errorf("Tags = %v, want [acme]", p.Tags)
+	}
+	if len(p.Moves) != 1 || p.Moves[0].From != "/older/api" {
+		t.Errorf("Moves = %v, want the old history", p.Moves)
+	}
+	// Scanned data is the new project's own.
+	if p.Path != "/new/api" || p.Status != "active" || p.Description != "new description" {
+		t.Errorf("scanned fields were overwritten: %+v", p)
+	}
+
+	// A project that already recorded moves keeps them.
+	q := &Project{Moves: []PathMove{{From: "/a", To: "/b"}}}
+	q.Inherit(old)
+	if len(q.Moves) != 1 || q.Moves[0].From != "/a" {
+		t.Errorf("Moves = %v, want its own", q.Moves)
+	}
+}
@@ -0,0 +1,52 @@
+package project
+
+import "strings"
+
+// Lifecycle marks set by the user with "prj mark". They survive rescans.
+const (
+	MarkArchived   = "archived"
+	MarkAbandoned  = "abandoned"
+	MarkIdea       = "idea"
+	MarkMaintained = "maintained"
+	MarkFocus      = "focus"
+)
+
+// Marks lists every valid mark.
+var Marks = []string{MarkFocus, MarkMaintained, MarkIdea, MarkArchived, MarkAbandoned}
+
+// overridingMarks replace the inferred status; the others decorate it.
+var overridingMarks = map[string]bool{
+	MarkArchived:  true,
+	MarkAbandoned: true,
+	MarkIdea:      true,
+}
+
+// ValidMark reports whether m is a known mark.
+func ValidMark(m string) bool {
+	for _, v := range Marks {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveStatus is the status to show and filter on: the mark for
+// archived, abandoned and idea projects, otherwise the inferred Status.
+func (p *Project) EffectiveStatus() string {
+	if overridingMarks[p.Mark] {
+		return p.Mark
+	}
+	return p.Status
+}
+
+// Dormant reports whether the user has said the project is meant to be
+// quiet, so it shouldn't be reported as stalled.
+func (p *Project) Dormant() bool {
+	return overridingMarks[p.Mark] || p.Mark == MarkMaintained
+}
+
+// MarksHelp renders the list of marks for help text.
+func MarksHelp() string {
+	return strings.Join(Marks, "|")
+}
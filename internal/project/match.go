@@ -0,0 +1,197 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Candidate is a project matching a query, with how well it matched.
+type Candidate struct {
+	Project *Project
+	Score   int
+}
+
+// Score tiers, best first. Within a tier, closer matches (shorter names,
+// fewer gaps) score higher, but never reach the next tier up.
+const (
+	scorePath      = 10000 // the query is the project's path
+	scoreQualified = 9000  // folder/name
+	scoreName      = 8000  // exact name
+	scorePrefix    = 7000  // name starts with the query
+	scoreSubstring = 6000  // name contains the query
+	scoreRemote    = 5000  // remote repo name is the query
+	scoreSegment   = 4000  // a path segment is the query
+	scoreInPath    = 3000  // path or remote contains the query
+	scoreFuzzy     = 2000  // query letters appear in order in the name
+	scoreFuzzyPath = 1000  // ... or in the path
+)
+
+// Rank scores every project against query (case-insensitive) and returns
+// the matches, best first. A query containing "/" also matches
+// "folder/name" against the end of a project's path.
+func Rank(projects []*Project, query string) []Candidate {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil
+	}
+	var out []Candidate
+	for _, p := range projects {
+		if s := score(p, query, q); s > 0 {
+			out = append(out, Candidate{Project: p, Score: s})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Project.Path < out[j].Project.Path
+	})
+	return out
+}
+
+func score(p *Project, raw, q string) int {
+	if p.Path == raw || p.Path == filepath.Clean(raw) {
+		return scorePath
+	}
+	name := strings.ToLower(p.Name)
+	path := strings.ToLower(filepath.ToSlash(p.Path))
+
+	if strings.Contains(q, "/") {
+		tail := strings.Trim(q, "/")
+		if strings.HasSuffix(path, "/"+tail) {
+			return scoreQualified + closeness(len(tail), len(path))
+		}
+		// work/api also finds ~/work/go/api.
+		folder, base := filepath.Split(tail)
+		if base == name && strings.Contains(filepath.Dir(path)+"/", "/"+folder) {
+			return scoreQualified
+		}
+		if strings.Contains(path, q) {
+			return scoreInPath + closeness(len(q), len(path))
+		}
+		return 0
+	}
+
+	switch {
+	case name == q:
+		return scoreName
+	case strings.HasPrefix(name, q):
+		return scorePrefix + closeness(len(q), len(name))
+	case strings.Contains(name, q):
+		return scoreSubstring + closeness(len(q), len(name))
+	}
+
+	remote := NormalizeRemote(p.GitRemote)
+	if remote != "" && filepath.Base(remote) == q {
+		return scoreRemote
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == q {
+			return scoreSegment
+		}
+	}
+	if strings.Contains(path, q) || strings.Contains(remote, q) {
+		return scoreInPath + closeness(len(q), len(path))
+	}
+	if gaps, ok := subsequence(q, name); ok {
+		return scoreFuzzy + closeness(len(q), len(q)+gaps)
+	}
+	if gaps, ok := subsequence(q, path); ok {
+		return scoreFuzzyPath + closeness(len(q), len(q)+gaps)
+	}
+	return 0
+}
+
+// closeness is 0-999: how much of the matched text the query covers.
+func closeness(matched, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return 999 * matched / total
+}
+
+// subsequence reports whether q's letters appear in s in order, and how
+// many letters of s lie between the first and last matched ones.
+func subsequence(q, s string) (gaps int, ok bool) {
+	qr, sr := []rune(q), []rune(s)
+	start, i := -1, 0
+	for j := 0; j < len(sr) && i < len(qr); j++ {
+		if sr[j] == qr[i] {
+			if start < 0 {
+				start = j
+			}
+			i++
+			if i == len(qr) {
+				return j - start + 1 - len(qr), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// AmbiguousError is returned by Resolve when several projects match a
+// query equally well.
+type AmbiguousError struct {
+	Query      string
+	Candidates []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d projects; use folder/name or the full path:", e.Query, len(e.Candidates))
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %-20s %s", c.Project.Name, c.Project.Path)
+	}
+	return b.String()
+}
+
+// Resolve returns the single best match for query. It returns nil and no
+// error when nothing matches, and an *AmbiguousError listing the tied
+// candidates when the best score is shared.
+func Resolve(projects []*Project, query string) (*Project, error) {
+	return ResolveBy(projects, query, nil)
+}
+
+// ResolveBy is Resolve with a preference: among the matches in the best
+// tier (an exact name, a name prefix, ...), the one prefer weighs highest
+// wins outright, even over a closer match in the same tier. Without a
+// positive, unique winner it falls back to Resolve's rules.
+func ResolveBy(projects []*Project, query string, prefer func(*Project) float64) (*Project, error) {
+	ranked := Rank(projects, query)
+	if len(ranked) == 0 {
+		return nil, nil
+	}
+	if prefer != nil {
+		var best *Project
+		var top float64
+		tie := false
+		for _, c := range ranked {
+			if tier(c.Score) != tier(ranked[0].Score) {
+				break
+			}
+			switch w := prefer(c.Project); {
+			case w > top:
+				best, top, tie = c.Project, w, false
+			case w == top:
+				tie = true
+			}
+		}
+		if best != nil && !tie {
+			return best, nil
+		}
+	}
+	tied := 1
+	for tied < len(ranked) && ranked[tied].Score == ranked[0].Score {
+		tied++
+	}
+	if tied > 1 {
+		return nil, &AmbiguousError{Query: query, Candidates: ranked[:tied]}
+	}
+	return ranked[0].Project, nil
+}
+
+func tier(score int) int {
+	return score / 1000
+}
@@ -0,0 +1,132 @@
+package project
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func matchFixture() []*Project {
+	return []*Project{
+		{Name: "api", Path: "/dev/work/api"},
+		{Name: "api", Path: "/dev/play/api"},
+		{Name: "apigen", Path: "/dev/tools/apigen"},
+		{Name: "my-webapp", Path: "/dev/my-webapp"},
+		{Name: "site", Path: "/dev/site", GitRemote: "git@github.com:u/homepage.git"},
+		{Name: "web", Path: "/dev/clients/acme/web"},
+		{Name: "mapper", Path: "/dev/legacy/mapper"},
+	}
+}
+
+func TestRankTiers(t *testing.T) {
+	tests := []struct {
+		query string
+		path  string // best match
+		tier  int
+	}{
+		{"/dev/work/api", "/dev/work/api", scorePath},
+		{"/dev/work/api/", "/dev/work/api", scorePath},
+		{"work/api", "/dev/work/api", scoreQualified},
+		{"WORK/API", "/dev/work/api", scoreQualified},
+		{"dev/api", "/dev/play/api", scoreQualified}, // folder anywhere above, ties by path
+		{"apigen", "/dev/tools/apigen", scoreName},
+		{"apig", "/dev/tools/apigen", scorePrefix},
+		{"webapp", "/dev/my-webapp", scoreSubstring},
+		{"homepage", "/dev/site", scoreRemote},
+		{"acme", "/dev/clients/acme/web", scoreSegment},
+		{"lient", "/dev/clients/acme/web", scoreInPath},
+		{"mpr", "/dev/legacy/mapper", scoreFuzzy},
+		{"dvlgc", "/dev/legacy/mapper", scoreFuzzyPath},
+	}
+	for _, tt := range tests {
+		ranked := Rank(matchFixture(), tt.query)
+		if len(ranked) == 0 {
+			t.Errorf("Rank(%q): no matches", tt.query)
+			continue
+		}
+		got := ranked[0]
+		if got.Project.Path != tt.path || tier(got.Score) != tier(tt.tier) {
+			t.Errorf("Rank(%q)[0] = %s (score %d), want %s in tier %d", tt.query, got.Project.Path, got.Score, tt.path, tt.tier)
+		}
+	}
+}
+
+func TestRankOrder(t *testing.T) {
+	// Exact names, then prefixes with the closer one first; ties by path.
+	ranked := Rank(matchFixture(), "api")
+	var paths []string
+	for _, c := range ranked {
+		paths = append(paths, c.Project.Path)
+	}
+	want := []string{"/dev/play/api", "/dev/work/api", "/dev/tools/apigen"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("Rank(api) = %v, want %v", paths, want)
+	}
+
+	if got := Rank(matchFixture(), "  "); got != nil {
+		t.Errorf("Rank(blank) = %v, want nil", got)
+	}
+	if got := Rank(matchFixture(), "zzz"); len(got) != 0 {
+		t.Errorf("Rank(zzz) = %v, want no matches", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	p, err := Resolve(matchFixture(), "apigen")
+	if err != nil || p == nil || p.Path != "/dev/tools/apigen" {
+		t.Errorf("Resolve(apigen) = %v, %v", p, err)
+	}
+
+	p, err = Resolve(matchFixture(), "zzz")
+	if p != nil || err != nil {
+		t.Errorf("Resolve(zzz) = %v, %v; want nil, nil", p, err)
+	}
+
+	_, err = Resolve(matchFixture(), "api")
+	var amb *AmbiguousError
+	if !errors.As(err, &amb) {
+		t.Fatalf("Resolve(api) error = %v, want *AmbiguousError", err)
+	}
+	if len(amb.Candidates) != 2 {
+		t.Errorf("Resolve(api) candidates = %d, want the 2 exact names", len(amb.Candidates))
+	}
+	for _, path := range []string{"/dev/work/api", "/dev/play/api"} {
+		if !strings.Contains(amb.Error(), path) {
+			t.Errorf("AmbiguousError %q doesn't list %s", amb.Error(), path)
+		}
+	}
+}
+
+func TestResolveByPreference(t *testing.T) {
+	weights := func(w map[string]float64) func(*Project) float64 {
+		return func(p *Project) float64 { return w[p.Path] }
+	}
+	tests := []struct {
+		name  string
+		query string
+		prefs map[string]float64
+		want  string // "" means ambiguous
+	}{
+		{"preference breaks an exact tie", "api", map[string]float64{"/dev/work/api": 3}, "/dev/work/api"},
+		{"higher preference wins", "api", map[string]float64{"/dev/work/api": 1, "/dev/play/api": 2}, "/dev/play/api"},
+		{"equal preferences stay ambiguous", "api", map[string]float64{"/dev/work/api": 2, "/dev/play/api": 2}, ""},
+		{"no preference stays ambiguous", "api", nil, ""},
+		{"lower tier can't win", "api", map[string]float64{"/dev/tools/apigen": 9}, ""},
+		// Same tier (name prefix), so preference beats the closer match.
+		{"preference beats closeness in a tier", "ap", map[string]float64{"/dev/tools/apigen": 1}, "/dev/tools/apigen"},
+		{"clear winner ignores preference", "apigen", map[string]float64{"/dev/work/api": 9}, "/dev/tools/apigen"},
+	}
+	for _, tt := range tests {
+		p, err := ResolveBy(matchFixture(), tt.query, weights(tt.prefs))
+		if tt.want == "" {
+			var amb *AmbiguousError
+			if !errors.As(err, &amb) {
+				t.Errorf("%s: ResolveBy(%q) = %v, %v; want ambiguous", tt.name, tt.query, p, err)
+			}
+			continue
+		}
+		if err != nil || p == nil || p.Path != tt.want {
+			t.Errorf("%s: ResolveBy(%q) = %v, %v; want %s", tt.name, tt.query, p, err, tt.want)
+		}
+	}
+}
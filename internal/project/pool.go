@@ -0,0 +1,62 @@
+package project
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ExtractAll runs Refresh for every repo path using up to jobs concurrent
+// workers (one per CPU when jobs < 1), reusing the project in prev (keyed by path) when the repo is
+// unchanged. With full set every repo is re-extracted, still inheriting
+// prev's annotations. The classifier assigns each repo's status. Results
+// are returned in the same order as paths, regardless of which worker
+// finishes first.
+// If progress is non-nil it is called once per repo as it completes, with
+// done counting up from 1; calls are serialized so callers can print
+// without extra locking.
+func ExtractAll(paths []string, jobs int, c *Classifier, prev map[string]*Project, full bool, progress func(done int, path string, reused bool)) []*Project {
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > len(paths) {
+		jobs = len(paths)
+	}
+
+	results := make([]*Project, len(paths))
+	work := make(chan int)
+
+	var mu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				var p *Project
+				reused := false
+				if full {
+					p = Extract(paths[i], prev[paths[i]], c)
+				} else {
+					p, reused = Refresh(paths[i], prev[paths[i]], c)
+				}
+				results[i] = p
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, paths[i], reused)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range paths {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,31 @@
+package project
+
+import "testing"
+
+func TestExtractAllOrder(t *testing.T) {
+	names := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}
+	paths := make([]string, len(names))
+	for i, n := range names {
+		paths[i] = gitRepo(t, n)
+	}
+	c, err := NewClassifier(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := 0
+	got := ExtractAll(paths, 4, c, nil, false, func(n int, path string, reused bool) {
+		done++
+		if n != done {
+			t.Errorf("progress done = %d, want %d", n, done)
+		}
+	})
+	if len(got) != len(paths) || done != len(paths) {
+		t.Fatalf("%d results and %d progress calls for %d paths", len(got), done, len(paths))
+	}
+	for i, p := range got {
+		if p == nil || p.Path != paths[i] || p.Name != names[i] {
+			t.Errorf("results[%d] = %+v, want %s", i, p, paths[i])
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type Project struct {
+	ID                string              `json:"id,omitempty"`
 	Name              string              `json:"name"`
 	Path              string              `json:"path"`
 	Description       string              `json:"description"`
@@ -21,6 +22,8 @@ type Project struct {
 	LastCommitDate    string              `json:"last_commit_date"`
 	LastCommitMessage string              `json:"last_commit_message"`
 	LastCommitAuthor  string              `json:"last_commit_author"`
+	Dirty             bool                `json:"dirty,omitempty"`
+	Unpushed          int                 `json:"unpushed,omitempty"`
 	RecentCommits     []scanner.CommitInfo `json:"recent_commits"`
 	CommitCount8M     int                 `json:"commit_count_8m"`
 	Contributors      []string            `json:"contributors"`
@@ -33,6 +36,14 @@ type Project struct {
 	AIDocsCount       int                 `json:"ai_docs_count"`
 	Errors            []string            `json:"errors,omitempty"`
 	ScannedAt         string              `json:"scanned_at"`
+	Fingerprint       string              `json:"fingerprint,omitempty"`
+	Missing           string              `json:"missing,omitempty"`
+	Moves             []PathMove          `json:"moves,omitempty"`
+	Mark              string              `json:"mark,omitempty"`
+	MarkedAt          string              `json:"marked_at,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	Note              string              `json:"note,omitempty"`
+	NoteUpdatedAt     string              `json:"note_updated_at,omitempty"`
 }
 
 type ReferenceFiles struct {
@@ -44,6 +55,8 @@ type ReferenceFiles struct {
 }
 
 // ExtractFromPath scans a git repo at the given path and returns a Project.
+// It leaves Status and the TODO counts unset: status rules can read
+// annotations, so callers classify once those are inherited.
 func ExtractFromPath(repoPath string) *Project {
 	p := &Project{
 		Name:      filepath.Base(repoPath),
@@ -51,6 +64,9 @@ func ExtractFromPath(repoPath string) *Project {
 		ScannedAt: time.Now().UTC().Format(time.RFC3339),
 	}
 
+	// Taken before extraction so a change made mid-scan is picked up next time
+	p.Fingerprint = Fingerprint(repoPath)
+
 	// Git data
 	commits, err := scanner.RecentCommits(repoPath, 10)
 	if err != nil {
@@ -67,6 +83,8 @@ func ExtractFromPath(repoPath string) *Project {
 	p.CommitCount8M = scanner.CommitCountSince(repoPath, "8 months ago")
 	p.Contributors = scanner.Contributors(repoPath)
 	p.GitRemote = scanner.Remote(repoPath)
+	p.ID = StableID(scanner.RootCommit(repoPath), p.GitRemote)
+	p.Dirty, p.Unpushed = scanner.WorkingState(repoPath)
 
 	// Fork detection
 	p.IsFork = detectFork(repoPath, p.GitRemote)
@@ -83,9 +101,6 @@ func ExtractFromPath(repoPath string) *Project {
 	// Description
 	p.Description, p.ClaudeDescription = ExtractDescription(repoPath)
 
-	// State
-	p.Status, p.TodoOpen, p.TodoClosed = InferState(repoPath, p.LastCommitDate, p.RecentCommits)
-
 	// Deployment
 	p.Deployment = DetectDeployment(repoPath)
 
@@ -95,6 +110,17 @@ func ExtractFromPath(repoPath string) *Project {
 	return p
 }
 
+// Inherit copies what a scan doesn't produce — user annotations and the
+// move history — from the stored version of the same project.
+func (p *Project) Inherit(old *Project) {
+	if len(p.Moves) == 0 {
+		p.Moves = old.Moves
+	}
+	p.Mark, p.MarkedAt = old.Mark, old.MarkedAt
+	p.Tags = old.Tags
+	p.Note, p.NoteUpdatedAt = old.Note, old.NoteUpdatedAt
+}
+
 func detectFork(dir, remote string) bool {
 	if remote == "" {
 		return false
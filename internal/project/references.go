@@ -5,16 +5,18 @@ import (
 	"path/filepath"
 )
 
+// rootReferenceFiles are the well-known files looked for at the repo root.
+var rootReferenceFiles = []string{
+	"README.md", "CLAUDE.md", "AGENT.md", "CHANGELOG.md",
+	"TODO.md", "CONTRIBUTING.md", "LICENSE",
+}
+
 // FindReferences scans for reference files across the repo.
 func FindReferences(dir string) ReferenceFiles {
 	ref := ReferenceFiles{}
 
 	// Root reference files
-	rootFiles := []string{
-		"README.md", "CLAUDE.md", "AGENT.md", "CHANGELOG.md",
-		"TODO.md", "CONTRIBUTING.md", "LICENSE",
-	}
-	for _, name := range rootFiles {
+	for _, name := range rootReferenceFiles {
 		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
 			ref.Root = append(ref.Root, name)
 		}
@@ -0,0 +1,28 @@
+package project
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKeys are the orders SortBy understands.
+var SortKeys = []string{"date", "name", "commits"}
+
+// SortBy sorts projects in place: "name" alphabetically, "commits" most
+// active first, anything else ("date") by last commit, newest first.
+func SortBy(projects []*Project, by string) {
+	switch by {
+	case "name":
+		sort.Slice(projects, func(i, j int) bool {
+			return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
+		})
+	case "commits":
+		sort.Slice(projects, func(i, j int) bool {
+			return projects[i].CommitCount8M > projects[j].CommitCount8M
+		})
+	default: // "date" or empty — sort by last commit desc
+		sort.Slice(projects, func(i, j int) bool {
+			return projects[i].LastCommitDate > projects[j].LastCommitDate
+		})
+	}
+}
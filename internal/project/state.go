@@ -7,33 +7,39 @@ import (
 	"strings"
 	"time"
 
+	"github.com/peeomid/prj/internal/config"
 	"github.com/peeomid/prj/internal/scanner"
 )
 
 // InferState determines project status based on commit recency, TODOs, and commit messages.
 // Returns (status, todoOpen, todoClosed).
-func InferState(dir, lastCommitDate string, commits []scanner.CommitInfo) (string, int, int) {
+func InferState(dir, lastCommitDate string, commits []scanner.CommitInfo, th config.Thresholds) (string, int, int) {
 	todoOpen, todoClosed := parseTodoFile(filepath.Join(dir, "TODO.md"))
 
 	// Check commit messages for WIP keywords
 	hasWIP := false
 	for _, c := range commits {
 		msg := strings.ToLower(c.Message)
-		if strings.Contains(msg, "wip") || strings.Contains(msg, "work in progress") {
-			hasWIP = true
+		for _, kw := range th.WIPKeywords {
+			if kw != "" && strings.Contains(msg, strings.ToLower(kw)) {
+				hasWIP = true
+				break
+			}
+		}
+		if hasWIP {
 			break
 		}
 	}
 
-	if hasWIP && isRecent(lastCommitDate, 30) {
+	if hasWIP && isRecent(lastCommitDate, th.ActiveDays) {
 		return "wip", todoOpen, todoClosed
 	}
 
-	if isRecent(lastCommitDate, 30) {
+	if isRecent(lastCommitDate, th.ActiveDays) {
 		return "active", todoOpen, todoClosed
 	}
 
-	if isRecent(lastCommitDate, 90) {
+	if isRecent(lastCommitDate, th.CutoffDays) {
 		return "recent", todoOpen, todoClosed
 	}
 
@@ -0,0 +1,9 @@
+//go:build !(darwin || linux || freebsd || netbsd || openbsd || dragonfly)
+
+package runner
+
+import "os/exec"
+
+// Process groups aren't used on this platform; cancellation kills only the
+// command itself, and WaitDelay bounds the wait for its children.
+func killGroup(cmd *exec.Cmd) {}
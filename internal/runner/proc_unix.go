@@ -0,0 +1,17 @@
+//go:build darwin || linux || freebsd || netbsd || openbsd || dragonfly
+
+package runner
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killGroup makes cancellation kill the command's whole process group, so
+// a shell's children die with it instead of outliving the run.
+func killGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
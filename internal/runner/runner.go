@@ -0,0 +1,148 @@
+// Package runner runs one command in many directories at once, for
+// "prj exec".
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Target is one directory to run the command in.
+type Target struct {
+	Name string
+	Dir  string
+	// Env is added to the command's environment.
+	Env []string
+}
+
+// Result is how the command went in one target.
+type Result struct {
+	Target   Target
+	ExitCode int // -1 if the command couldn't start or was killed
+	Err      error
+	Duration time.Duration
+	// Skipped targets never ran: --fail-fast stopped the run first.
+	Skipped bool
+	// Canceled targets were running when the run was stopped, by
+	// --fail-fast or an interrupt.
+	Canceled bool
+}
+
+// OK reports whether the command ran and exited 0.
+func (r Result) OK() bool {
+	return !r.Skipped && !r.Canceled && r.Err == nil
+}
+
+// Options control a run.
+type Options struct {
+	// Jobs is how many targets run at once; < 1 means one per CPU.
+	Jobs int
+	// FailFast stops starting targets after the first failure and kills
+	// the ones still running.
+	FailFast bool
+	// Output returns the writers for a target's stdout and stderr. It is
+	// called once per target, just before it starts. Without it, output is
+	// discarded.
+	Output func(t Target) (stdout, stderr io.Writer)
+	// Done, if set, is called as each target finishes (not for skipped
+	// ones). Calls are serialized.
+	Done func(r Result)
+}
+
+// Run runs argv in every target and returns the results in target order.
+// Cancelling ctx kills the commands still running and skips the rest.
+func Run(ctx context.Context, argv []string, targets []Target, opts Options) []Result {
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > len(targets) {
+		jobs = len(targets)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]Result, len(targets))
+	for i, t := range targets {
+		results[i] = Result{Target: t, ExitCode: -1, Skipped: true}
+	}
+	work := make(chan int)
+	var mu sync.Mutex
+
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				r := runOne(ctx, argv, targets[i], opts)
+				mu.Lock()
+				results[i] = r
+				if !r.OK() && opts.FailFast {
+					cancel()
+				}
+				if opts.Done != nil && !r.Skipped {
+					opts.Done(r)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range targets {
+		select {
+		case work <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
+
+func runOne(ctx context.Context, argv []string, t Target, opts Options) Result {
+	r := Result{Target: t, ExitCode: -1}
+	if ctx.Err() != nil {
+		r.Skipped = true
+		return r
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = t.Dir
+	cmd.Env = append(os.Environ(), t.Env...)
+	if opts.Output != nil {
+		cmd.Stdout, cmd.Stderr = opts.Output(t)
+	}
+	killGroup(cmd)
+	// Don't wait long for anything still holding the output open after a
+	// kill.
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	r.Err = cmd.Run()
+	r.Duration = time.Since(start)
+
+	// A command that exited on its own keeps its exit code even if the run
+	// was stopped meanwhile; only one killed by a signal was canceled.
+	var exitErr *exec.ExitError
+	switch {
+	case r.Err == nil:
+		r.ExitCode = 0
+	case errors.As(r.Err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+		r.Canceled = r.ExitCode == -1 && ctx.Err() != nil
+	case ctx.Err() != nil:
+		r.Canceled = true
+	}
+	return r
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// sh runs script in a target whose environment sets CODE and PAUSE, so one
+// argv can behave differently per target.
+var sh = []string{"sh", "-c", `sleep "$PAUSE"; exit "$CODE"`}
+
+func target(name, code, pause string) Target {
+	return Target{Name: name, Dir: ".", Env: []string{"CODE=" + code, "PAUSE=" + pause}}
+}
+
+func needShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+}
+
+func TestRunResultsInTargetOrder(t *testing.T) {
+	needShell(t)
+	// Later targets finish first.
+	targets := []Target{
+		target("a", "0", "0.3"),
+		target("b", "3", "0.2"),
+		target("c", "0", "0.1"),
+		target("d", "5", "0"),
+	}
+	var finished []string
+	results := Run(context.Background(), sh, targets, Options{
+		Jobs: 4,
+		Done: func(r Result) { finished = append(finished, r.Target.Name) },
+	})
+
+	wantCodes := []int{0, 3, 0, 5}
+	for i, r := range results {
+		if r.Target.Name != targets[i].Name || r.ExitCode != wantCodes[i] {
+			t.Errorf("results[%d] = %s exit %d, want %s exit %d", i, r.Target.Name, r.ExitCode, targets[i].Name, wantCodes[i])
+		}
+		if r.Skipped || r.Canceled || r.OK() != (wantCodes[i] == 0) {
+			t.Errorf("results[%d] = %+v", i, r)
+		}
+	}
+	if len(finished) != 4 || finished[0] != "d" {
+		t.Errorf("Done order = %v, want d first", finished)
+	}
+}
+
+func TestRunFailFastSkips(t *testing.T) {
+	needShell(t)
+	targets := []Target{
+		target("ok", "0", "0"),
+		target("bad", "3", "0"),
+		target("later", "0", "0"),
+		target("last", "0", "0"),
+	}
+	results := Run(context.Background(), sh, targets, Options{Jobs: 1, FailFast: true})
+
+	if !results[0].OK() {
+		t.Errorf("ok = %+v, want success", results[0])
+	}
+	if r := results[1]; r.ExitCode != 3 || r.Canceled || r.Skipped {
+		t.Errorf("bad = %+v, want a plain failure with exit 3", r)
+	}
+	for _, r := range results[2:] {
+		if !r.Skipped || r.Canceled || r.ExitCode != -1 {
+			t.Errorf("%s = %+v, want skipped", r.Target.Name, r)
+		}
+	}
+}
+
+func TestRunFailFastCancels(t *testing.T) {
+	needShell(t)
+	targets := []Target{
+		target("bad", "2", "0.1"),
+		target("slow", "0", "30"),
+	}
+	start := time.Now()
+	results := Run(context.Background(), sh, targets, Options{Jobs: 2, FailFast: true})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("fail-fast run took %s; the slow target wasn't killed", elapsed)
+	}
+
+	if r := results[0]; r.ExitCode != 2 || r.Canceled {
+		t.Errorf("bad = %+v, want exit 2, not canceled", r)
+	}
+	if r := results[1]; !r.Canceled || r.Skipped || r.ExitCode != -1 || r.OK() {
+		t.Errorf("slow = %+v, want canceled", r)
+	}
+}
+
+func TestRunInterrupted(t *testing.T) {
+	needShell(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	targets := []Target{
+		target("slow", "0", "30"),
+		target("queued", "0", "0"),
+	}
+	results := Run(ctx, sh, targets, Options{Jobs: 1})
+	if r := results[0]; !r.Canceled || r.ExitCode != -1 {
+		t.Errorf("slow = %+v, want canceled", r)
+	}
+	if r := results[1]; !r.Skipped {
+		t.Errorf("queued = %+v, want skipped", r)
+	}
+}
@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -96,6 +97,44 @@ func Remote(dir string) string {
 	return out
 }
 
+// RootCommit returns the hash of the repo's first commit. Histories with
+// several roots (merged-in projects) return the lexically smallest so the
+// result doesn't depend on traversal order.
+func RootCommit(dir string) string {
+	lines, err := GitLines(dir, "rev-list", "--max-parents=0", "HEAD")
+	if err != nil || len(lines) == 0 {
+		return ""
+	}
+	root := lines[0]
+	for _, l := range lines[1:] {
+		if l < root {
+			root = l
+		}
+	}
+	return root
+}
+
+// WorkingState reports whether the working tree has uncommitted changes
+// (untracked files included) and how many commits the current branch is
+// ahead of its upstream. A branch without an upstream is 0 ahead.
+func WorkingState(dir string) (dirty bool, ahead int) {
+	lines, err := GitLines(dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return false, 0
+	}
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "# ") {
+			dirty = true
+			continue
+		}
+		if ab, ok := strings.CutPrefix(l, "# branch.ab +"); ok {
+			n, _, _ := strings.Cut(ab, " ")
+			ahead, _ = strconv.Atoi(n)
+		}
+	}
+	return dirty, ahead
+}
+
 // GitUserName returns the local or global git user.name.
 func GitUserName(dir string) string {
 	out, _ := Git(dir, "config", "user.name")
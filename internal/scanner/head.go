@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HeadRef resolves HEAD to "<ref> <hash>" by reading .git directly, without
+// spawning git. A detached HEAD returns just the hash. Falls back to
+// "git rev-parse HEAD" if the ref can't be found on disk.
+func HeadRef(dir string) string {
+	gitDir := filepath.Join(dir, ".git")
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(head, "ref: ") {
+		return head
+	}
+	ref := strings.TrimPrefix(head, "ref: ")
+
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return ref + " " + strings.TrimSpace(string(data))
+	}
+	if hash := packedRef(filepath.Join(gitDir, "packed-refs"), ref); hash != "" {
+		return ref + " " + hash
+	}
+
+	// Unborn branch or an unusual layout; ask git.
+	out, _ := Git(dir, "rev-parse", "HEAD")
+	return ref + " " + out
+}
+
+// packedRef looks up a ref in a packed-refs file.
+func packedRef(path, ref string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) == 2 && parts[1] == ref {
+			return parts[0]
+		}
+	}
+	return ""
+}
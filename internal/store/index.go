@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// IndexEntry is the little "prj prompt" needs to know about a project.
+type IndexEntry struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"` // effective status
+	Mark     string `json:"mark,omitempty"`
+	TodoOpen int    `json:"todo_open,omitempty"`
+	Dirty    bool   `json:"dirty,omitempty"`
+	Unpushed int    `json:"unpushed,omitempty"`
+}
+
+// Index maps project paths to their entries. Both backends rewrite it on
+// every change, so a shell prompt can read a few kilobytes instead of
+// decoding the whole store.
+type Index map[string]IndexEntry
+
+// IndexPath is the index file, shared by both backends.
+func IndexPath() string {
+	return filepath.Join(config.Dir(), "index.json")
+}
+
+// LoadIndex reads the index. It returns an os.IsNotExist error when no
+// store has been written since the index was introduced; BuildIndex
+// creates it.
+func LoadIndex() (Index, error) {
+	data, err := os.ReadFile(IndexPath())
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// BuildIndex writes the index for projects and returns it.
+func BuildIndex(projects []*project.Project) (Index, error) {
+	idx := make(Index, len(projects))
+	for _, p := range projects {
+		if p.Missing != "" {
+			continue
+		}
+		idx[p.Path] = IndexEntry{
+			Name:     p.Name,
+			Status:   p.EffectiveStatus(),
+			Mark:     p.Mark,
+			TodoOpen: p.TodoOpen,
+			Dirty:    p.Dirty,
+			Unpushed: p.Unpushed,
+		}
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		return nil, err
+	}
+	return idx, fsutil.WriteFileAtomic(IndexPath(), data, 0644)
+}
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Path is the projects.json file used by the JSON backend.
+func Path() string {
+	return filepath.Join(config.Dir(), "projects.json")
+}
+
+// JSONStore keeps every project in a single versioned JSON file. Every
+// write rewrites the whole file.
+type JSONStore struct {
+	path string
+}
+
+func (s *JSONStore) Location() string { return s.path }
+
+func (s *JSONStore) Close() error { return nil }
+
+// Load reads the projects from disk, upgrading older schema versions in
+// memory. The upgrade is written back on the next Save.
+func (s *JSONStore) Load() ([]*project.Project, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	env, err := decodeEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(env); err != nil {
+		return nil, err
+	}
+	projects, err := decodeProjects(env.Projects)
+	if err != nil {
+		return nil, err
+	}
+	return sortByPath(projects), nil
+}
+
+// Save writes projects to disk, sorted by path so the file only changes
+// where the data does. generated_at is kept when the projects are the same
+// as on disk. The write is atomic and holds config.Lock.
+func (s *JSONStore) Save(projects []*project.Project) error {
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var prev *Envelope
+	if data, err := os.ReadFile(s.path); err == nil {
+		if prev, err = decodeEnvelope(data); err != nil {
+			return err
+		}
+		// Refuse to overwrite a file written by a newer prj.
+		if _, err := Pending(prev.SchemaVersion); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	raw, err := encodeProjects(sortByPath(projects))
+	if err != nil {
+		return err
+	}
+	generatedAt := ""
+	if prev != nil && prev.SchemaVersion == SchemaVersion && sameProjects(prev.Projects, raw) {
+		generatedAt = prev.GeneratedAt
+	}
+	data, err := encodeEnvelope(raw, generatedAt)
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(s.path, data, 0644); err != nil {
+		return err
+	}
+	_, err = BuildIndex(projects)
+	return err
+}
+
+func (s *JSONStore) Get(key string) (*project.Project, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if p.Path == key || (p.ID != "" && p.ID == key) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *JSONStore) Upsert(projects ...*project.Project) error {
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	existing, err := s.Load()
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]int, len(existing))
+	for i, p := range existing {
+		byPath[p.Path] = i
+	}
+	for _, p := range projects {
+		if i, ok := byPath[p.Path]; ok {
+			existing[i] = p
+			continue
+		}
+		byPath[p.Path] = len(existing)
+		existing = append(existing, p)
+	}
+	return s.Save(existing)
+}
+
+func (s *JSONStore) Delete(paths ...string) error {
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	existing, err := s.Load()
+	if err != nil {
+		return err
+	}
+	drop := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		drop[p] = true
+	}
+	kept := existing[:0]
+	for _, p := range existing {
+		if !drop[p.Path] {
+			kept = append(kept, p)
+		}
+	}
+	return s.Save(kept)
+}
+
+func (s *JSONStore) Query(q Query) ([]*project.Project, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	var result []*project.Project
+	for _, p := range projects {
+		if q.Match(p) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func decodeProjects(raw []json.RawMessage) ([]*project.Project, error) {
+	projects := make([]*project.Project, 0, len(raw))
+	for _, r := range raw {
+		p := &project.Project{}
+		if err := json.Unmarshal(r, p); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+func encodeProjects(projects []*project.Project) ([]json.RawMessage, error) {
+	raw := make([]json.RawMessage, len(projects))
+	for i, p := range projects {
+		var err error
+		if raw[i], err = json.Marshal(p); err != nil {
+			return nil, err
+		}
+	}
+	return raw, nil
+}
+
+func sortByPath(projects []*project.Project) []*project.Project {
+	sorted := make([]*project.Project, len(projects))
+	copy(sorted, projects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/fsutil"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Migration upgrades the projects in a store file from schema From to
+// From+1. Projects are passed as generic JSON objects so a migration can
+// rename, split or drop fields that no longer exist on project.Project.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(projects []map[string]any) ([]map[string]any, error)
+}
+
+// migrations must be contiguous and ordered by From.
+var migrations = []Migration{
+	{
+		From:        1,
+		Description: "wrap the bare project array in a versioned envelope",
+		Apply: func(projects []map[string]any) ([]map[string]any, error) {
+			return projects, nil
+		},
+	},
+}
+
+// Pending returns the migrations needed to bring a file at schema version
+// from up to SchemaVersion. A file newer than this build is an error: we
+// never downgrade, since that would silently drop fields.
+func Pending(from int) ([]Migration, error) {
+	if from > SchemaVersion {
+		return nil, fmt.Errorf("store is schema v%d but this prj only understands up to v%d; upgrade prj", from, SchemaVersion)
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if m.From >= from {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// migrate upgrades env in memory to SchemaVersion.
+func migrate(env *Envelope) error {
+	pending, err := Pending(env.SchemaVersion)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	projects := make([]map[string]any, len(env.Projects))
+	for i, raw := range env.Projects {
+		if err := json.Unmarshal(raw, &projects[i]); err != nil {
+			return err
+		}
+	}
+	for _, m := range pending {
+		projects, err = m.Apply(projects)
+		if err != nil {
+			return fmt.Errorf("migrate v%d → v%d: %w", m.From, m.From+1, err)
+		}
+		env.SchemaVersion = m.From + 1
+	}
+	env.Projects = make([]json.RawMessage, len(projects))
+	for i, p := range projects {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		env.Projects[i] = raw
+	}
+	return nil
+}
+
+// FileVersion reports the schema version of projects.json on disk, or 0 if
+// there is no store yet.
+func FileVersion() (int, error) {
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	env, err := decodeEnvelope(data)
+	if err != nil {
+		return 0, err
+	}
+	return env.SchemaVersion, nil
+}
+
+// Migrate upgrades projects.json on disk to SchemaVersion, keeping a copy of
+// the original next to it as projects.json.v<N>.bak. Returns the backup path,
+// or "" if the file was already current.
+func Migrate() (string, error) {
+	unlock, err := config.Lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	env, err := decodeEnvelope(data)
+	if err != nil {
+		return "", err
+	}
+	from := env.SchemaVersion
+	if err := migrate(env); err != nil {
+		return "", err
+	}
+	if from == env.SchemaVersion {
+		return "", nil
+	}
+
+	// Round-trip through project.Project so the file gets the same field
+	// order a normal Save would produce.
+	for i, raw := range env.Projects {
+		var p project.Project
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return "", err
+		}
+		if env.Projects[i], err = json.Marshal(&p); err != nil {
+			return "", err
+		}
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", Path(), from)
+	if err := fsutil.WriteFileAtomic(backup, data, 0644); err != nil {
+		return "", err
+	}
+	out, err := encodeEnvelope(env.Projects, "")
+	if err != nil {
+		return "", err
+	}
+	return backup, fsutil.WriteFileAtomic(Path(), out, 0644)
+}
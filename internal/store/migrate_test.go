@@ -0,0 +1,191 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// tempHome points config.Dir at an empty directory for the test.
+func tempHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeStore(t *testing.T, data string) {
+	t.Helper()
+	if err := os.WriteFile(Path(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A v1 store: the bare array written before the envelope existed.
+const v1Store = `[
+  {"name": "api", "path": "/dev/api", "status": "active", "tech_stack": ["go"], "mark": "focus"},
+  {"name": "web", "path": "/dev/web", "status": "paused", "tags": ["acme"]}
+]`
+
+func TestLoadMigratesV1InMemory(t *testing.T) {
+	tempHome(t)
+	writeStore(t, v1Store)
+
+	projects, err := (&JSONStore{path: Path()}).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("loaded %d projects, want 2", len(projects))
+	}
+	if p := projects[0]; p.Name != "api" || p.Mark != "focus" || len(p.TechStack) != 1 {
+		t.Errorf("projects[0] = %+v", p)
+	}
+	if p := projects[1]; p.Name != "web" || len(p.Tags) != 1 || p.Tags[0] != "acme" {
+		t.Errorf("projects[1] = %+v", p)
+	}
+
+	// Loading alone doesn't touch the file.
+	if v, err := FileVersion(); err != nil || v != 1 {
+		t.Errorf("FileVersion after Load = %d, %v; want 1", v, err)
+	}
+}
+
+func TestMigrateV1ToCurrent(t *testing.T) {
+	tempHome(t)
+	writeStore(t, v1Store)
+
+	pending, err := Pending(1)
+	if err != nil || len(pending) != SchemaVersion-1 {
+		t.Fatalf("Pending(1) = %d migrations, %v; want %d", len(pending), err, SchemaVersion-1)
+	}
+
+	backup, err := Migrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Path() + ".v1.bak"; backup != want {
+		t.Errorf("backup = %q, want %q", backup, want)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != v1Store {
+		t.Errorf("backup doesn't hold the original file (%v)", err)
+	}
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("migrated file isn't an envelope: %v", err)
+	}
+	if env.SchemaVersion != SchemaVersion || len(env.Projects) != 2 {
+		t.Errorf("migrated envelope: schema v%d with %d projects", env.SchemaVersion, len(env.Projects))
+	}
+	var p project.Project
+	if err := json.Unmarshal(env.Projects[0], &p); err != nil || p.Name != "api" || p.Mark != "focus" {
+		t.Errorf("migrated projects[0] = %+v, %v", p, err)
+	}
+
+	// A current file needs nothing.
+	if backup, err := Migrate(); err != nil || backup != "" {
+		t.Errorf("second Migrate = %q, %v; want no-op", backup, err)
+	}
+}
+
+func TestMigrateWithoutStore(t *testing.T) {
+	tempHome(t)
+	if backup, err := Migrate(); err != nil || backup != "" {
+		t.Errorf("Migrate = %q, %v; want no-op", backup, err)
+	}
+	if v, err := FileVersion(); err != nil || v != 0 {
+		t.Errorf("FileVersion = %d, %v; want 0", v, err)
+	}
+}
+
+func TestRefuseNewerSchema(t *testing.T) {
+	tempHome(t)
+	newer := `{"schema_version": 99, "projects": [{"name": "api", "path": "/dev/api"}]}`
+	writeStore(t, newer)
+
+	st := &JSONStore{path: Path()}
+	if _, err := st.Load(); err == nil || !strings.Contains(err.Error(), "upgrade prj") {
+		t.Errorf("Load = %v, want an upgrade error", err)
+	}
+	if _, err := Migrate(); err == nil {
+		t.Error("Migrate of a newer store succeeded")
+	}
+	if err := st.Save([]*project.Project{{Name: "x", Path: "/dev/x"}}); err == nil {
+		t.Error("Save over a newer store succeeded")
+	}
+	if data, _ := os.ReadFile(Path()); string(data) != newer {
+		t.Error("the newer store was modified")
+	}
+	if _, err := os.Stat(filepath.Join(config.Dir(), "projects.json.v99.bak")); err == nil {
+		t.Error("a backup was written for a newer store")
+	}
+}
+
+func TestRejectMissingSchemaVersion(t *testing.T) {
+	tempHome(t)
+	writeStore(t, `{"projects": []}`)
+	if _, err := (&JSONStore{path: Path()}).Load(); err == nil {
+		t.Error("Load of an envelope without schema_version succeeded")
+	}
+}
+
+func TestSaveKeepsGeneratedAtWhenUnchanged(t *testing.T) {
+	tempHome(t)
+	st := &JSONStore{path: Path()}
+	readEnv := func() Envelope {
+		t.Helper()
+		data, err := os.ReadFile(Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			t.Fatal(err)
+		}
+		return env
+	}
+
+	// A store saved long ago.
+	const stamp = "2020-01-01T00:00:00Z"
+	if err := st.Save([]*project.Project{{Name: "api", Path: "/dev/api", ScannedAt: stamp}}); err != nil {
+		t.Fatal(err)
+	}
+	env := readEnv()
+	env.GeneratedAt = stamp
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeStore(t, string(data))
+
+	projects, err := st.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Save(projects); err != nil {
+		t.Fatal(err)
+	}
+	if got := readEnv().GeneratedAt; got != stamp {
+		t.Errorf("generated_at after an unchanged save = %s, want %s", got, stamp)
+	}
+
+	projects[0].Note = "changed"
+	if err := st.Save(projects); err != nil {
+		t.Fatal(err)
+	}
+	if got := readEnv().GeneratedAt; got == stamp {
+		t.Error("generated_at kept after the projects changed")
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+// MissingReason reports why a stored project no longer belongs in the store,
+// or "" if its repo still exists under one of the tracked folders.
+func MissingReason(p *project.Project, folders []string) string {
+	if _, err := os.Stat(p.Path); err != nil {
+		return "path not found"
+	}
+	// Worktrees and submodules have a .git file pointing at the real one.
+	if info, err := os.Stat(filepath.Join(p.Path, ".git")); err != nil || !(info.IsDir() || info.Mode().IsRegular()) {
+		return "not a git repository"
+	}
+	if !underAny(p.Path, folders) {
+		return "outside tracked folders"
+	}
+	return ""
+}
+
+// MarkMissing sets Missing on every project whose repo is gone or no longer
+// under a tracked folder, and clears it on the rest. Returns how many are
+// missing.
+func MarkMissing(projects []*project.Project, folders []string) int {
+	n := 0
+	for _, p := range projects {
+		p.Missing = MissingReason(p, folders)
+		if p.Missing != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Prune splits projects into those to keep and those marked missing.
+func Prune(projects []*project.Project) (kept, removed []*project.Project) {
+	for _, p := range projects {
+		if p.Missing != "" {
+			removed = append(removed, p)
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	return kept, removed
+}
+
+func underAny(path string, folders []string) bool {
+	for _, f := range folders {
+		if path == f || strings.HasPrefix(path, f+string(os.PathSeparator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+func TestMissingReason(t *testing.T) {
+	root := t.TempDir()
+	mkdir := func(rel string) string {
+		dir := filepath.Join(root, rel)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	repo := mkdir("dev/repo")
+	mkdir("dev/repo/.git")
+	worktree := mkdir("dev/worktree")
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: /dev/repo/.git/worktrees/wt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plain := mkdir("dev/plain")
+	outside := mkdir("other/repo")
+	mkdir("other/repo/.git")
+
+	folders := []string{filepath.Join(root, "dev")}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{repo, ""},
+		{worktree, ""},
+		{plain, "not a git repository"},
+		{filepath.Join(root, "dev/gone"), "path not found"},
+		{outside, "outside tracked folders"},
+	}
+	for _, tt := range tests {
+		if got := MissingReason(&project.Project{Path: tt.path}, folders); got != tt.want {
+			t.Errorf("MissingReason(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the projects.json layout this build reads and writes.
+// Bump it and append to migrations whenever the on-disk shape changes.
+const SchemaVersion = 2
+
+// AppVersion is the prj version recorded in files we write. The cmd package
+// sets it from its own Version at startup.
+var AppVersion = "dev"
+
+// Envelope is the top-level object of projects.json.
+type Envelope struct {
+	SchemaVersion int               `json:"schema_version"`
+	PrjVersion    string            `json:"prj_version"`
+	GeneratedAt   string            `json:"generated_at"`
+	Projects      []json.RawMessage `json:"projects"`
+}
+
+// decodeEnvelope parses a store file of any schema version. Version 1 files
+// predate the envelope and are a bare array of projects.
+func decodeEnvelope(data []byte) (*Envelope, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return &Envelope{SchemaVersion: SchemaVersion}, nil
+	}
+	if trimmed[0] == '[' {
+		env := &Envelope{SchemaVersion: 1}
+		if err := json.Unmarshal(trimmed, &env.Projects); err != nil {
+			return nil, err
+		}
+		return env, nil
+	}
+	env := &Envelope{}
+	if err := json.Unmarshal(trimmed, env); err != nil {
+		return nil, err
+	}
+	if env.SchemaVersion < 1 {
+		return nil, fmt.Errorf("store has no schema_version")
+	}
+	return env, nil
+}
+
+// encodeEnvelope wraps already-encoded projects in a current-version
+// envelope. An empty generatedAt means now.
+func encodeEnvelope(projects []json.RawMessage, generatedAt string) ([]byte, error) {
+	if generatedAt == "" {
+		generatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	env := Envelope{
+		SchemaVersion: SchemaVersion,
+		PrjVersion:    AppVersion,
+		GeneratedAt:   generatedAt,
+		Projects:      projects,
+	}
+	if env.Projects == nil {
+		env.Projects = []json.RawMessage{}
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// sameProjects reports whether two encoded project lists hold the same
+// JSON, ignoring formatting.
+func sameProjects(a, b []json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var ca, cb bytes.Buffer
+	for i := range a {
+		ca.Reset()
+		cb.Reset()
+		if json.Compact(&ca, a[i]) != nil || json.Compact(&cb, b[i]) != nil {
+			return false
+		}
+		if !bytes.Equal(ca.Bytes(), cb.Bytes()) {
+			return false
+		}
+	}
+	return true
+}
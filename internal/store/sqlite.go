@@ -0,0 +1,290 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/project"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registers "sqlite"
+)
+
+// SQLitePath is the database file used by the SQLite backend.
+func SQLitePath() string {
+	return filepath.Join(config.Dir(), "projects.db")
+}
+
+// SQLiteStore keeps one row per project. The full project is stored as JSON
+// in the data column; the columns next to it exist so Query can narrow
+// results with indexes instead of decoding every row.
+type SQLiteStore struct {
+	path string
+	db   *sql.DB
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS projects (
+	path          TEXT PRIMARY KEY,
+	id            TEXT NOT NULL DEFAULT '',
+	name          TEXT NOT NULL,
+	status        TEXT NOT NULL, -- effective status, see Project.EffectiveStatus
+	inferred_type TEXT NOT NULL,
+	tech          TEXT NOT NULL,
+	is_fork       INTEGER NOT NULL,
+	data          TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS projects_id ON projects(id);
+CREATE INDEX IF NOT EXISTS projects_status ON projects(status);
+CREATE INDEX IF NOT EXISTS projects_type ON projects(inferred_type);
+`
+
+// OpenSQLite opens (creating if needed) the database at path and upgrades
+// rows written by an older schema version.
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := &SQLiteStore{path: path, db: db}
+	if err := s.upgrade(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) Location() string { return s.path }
+
+func (s *SQLiteStore) Close() error { return s.db.Close() }
+
+// upgrade runs the same migrations as the JSON backend over the stored rows.
+func (s *SQLiteStore) upgrade() error {
+	version := SchemaVersion
+	var v string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = 'schema_version'`).Scan(&v)
+	switch {
+	case err == sql.ErrNoRows:
+		// New database.
+	case err != nil:
+		return err
+	default:
+		if version, err = strconv.Atoi(v); err != nil {
+			return fmt.Errorf("bad schema_version %q in %s", v, s.path)
+		}
+	}
+
+	pending, err := Pending(version)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		env := &Envelope{SchemaVersion: version}
+		if env.Projects, err = s.rawRows(`SELECT data FROM projects ORDER BY path`); err != nil {
+			return err
+		}
+		if err := migrate(env); err != nil {
+			return err
+		}
+		projects, err := decodeProjects(env.Projects)
+		if err != nil {
+			return err
+		}
+		if err := s.Save(projects); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.db.Exec(`INSERT INTO meta (key, value) VALUES ('schema_version', ?), ('prj_version', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, strconv.Itoa(SchemaVersion), AppVersion)
+	return err
+}
+
+func (s *SQLiteStore) Load() ([]*project.Project, error) {
+	return s.query(`SELECT data FROM projects ORDER BY path`)
+}
+
+func (s *SQLiteStore) Get(key string) (*project.Project, error) {
+	projects, err := s.query(`SELECT data FROM projects WHERE path = ? OR (id != '' AND id = ?) ORDER BY path LIMIT 1`, key, key)
+	if err != nil || len(projects) == 0 {
+		return nil, err
+	}
+	return projects[0], nil
+}
+
+func (s *SQLiteStore) Upsert(projects ...*project.Project) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := upsertRows(tx, projects); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.reindex()
+}
+
+func (s *SQLiteStore) Delete(paths ...string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, p := range paths {
+		if _, err := tx.Exec(`DELETE FROM projects WHERE path = ?`, p); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.reindex()
+}
+
+func (s *SQLiteStore) Save(projects []*project.Project) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM projects`); err != nil {
+		return err
+	}
+	if err := upsertRows(tx, projects); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	_, err = BuildIndex(projects)
+	return err
+}
+
+// reindex rebuilds the prompt index after a partial write.
+func (s *SQLiteStore) reindex() error {
+	projects, err := s.Load()
+	if err != nil {
+		return err
+	}
+	_, err = BuildIndex(projects)
+	return err
+}
+
+// Query narrows rows in SQL, then applies Query.Match so results are
+// identical to the JSON backend.
+func (s *SQLiteStore) Query(q Query) ([]*project.Project, error) {
+	var where []string
+	var args []any
+	if q.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, q.Status)
+	}
+	if q.Type != "" {
+		where = append(where, "inferred_type LIKE ?")
+		args = append(args, "%"+q.Type+"%")
+	}
+	if q.Tech != "" {
+		where = append(where, "tech LIKE ?")
+		args = append(args, "%"+q.Tech+"%")
+	}
+	if q.Own {
+		where = append(where, "is_fork = 0")
+	}
+	if q.Forks {
+		where = append(where, "is_fork = 1")
+	}
+	// Tag and Search also look at tags and the note, which only live in
+	// the data column; Match below handles them.
+
+	stmt := `SELECT data FROM projects`
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY path"
+
+	candidates, err := s.query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	var result []*project.Project
+	for _, p := range candidates {
+		if q.Match(p) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (s *SQLiteStore) query(stmt string, args ...any) ([]*project.Project, error) {
+	raw, err := s.rawRows(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeProjects(raw)
+}
+
+func (s *SQLiteStore) rawRows(stmt string, args ...any) ([]json.RawMessage, error) {
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var raw []json.RawMessage
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		raw = append(raw, json.RawMessage(data))
+	}
+	return raw, rows.Err()
+}
+
+func upsertRows(tx *sql.Tx, projects []*project.Project) error {
+	stmt, err := tx.Prepare(`INSERT INTO projects (path, id, name, status, inferred_type, tech, is_fork, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET
+			id = excluded.id, name = excluded.name, status = excluded.status,
+			inferred_type = excluded.inferred_type, tech = excluded.tech,
+			is_fork = excluded.is_fork, data = excluded.data`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, p := range projects {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		fork := 0
+		if p.IsFork {
+			fork = 1
+		}
+		if _, err := stmt.Exec(p.Path, p.ID, p.Name, p.EffectiveStatus(), p.InferredType,
+			strings.Join(p.TechStack, ","), fork, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/peeomid/prj/internal/expr"
+	"github.com/peeomid/prj/internal/project"
+)
+
+func storeFixture() []*project.Project {
+	return []*project.Project{
+		{ID: "id-api", Name: "api", Path: "/dev/work/api", Status: "active", InferredType: "go-app", TechStack: []string{"go"}, CommitCount8M: 40, Tags: []string{"Acme"}},
+		{ID: "id-web", Name: "web", Path: "/dev/work/web", Status: "wip", InferredType: "react-app", TechStack: []string{"node", "react"}, CommitCount8M: 12, Note: "launch in May"},
+		{Name: "old", Path: "/dev/old", Status: "active", InferredType: "go-app", TechStack: []string{"go"}, Mark: "archived"},
+		{ID: "id-fork", Name: "lib", Path: "/dev/forks/lib", Status: "paused", InferredType: "python-app", TechStack: []string{"python"}, IsFork: true, Mark: "focus"},
+		{Name: "goal", Path: "/dev/goal", Status: "recent", InferredType: "docs", TechStack: []string{"mongo"}},
+	}
+}
+
+func openSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	st, err := OpenSQLite(SQLitePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func paths(projects []*project.Project) string {
+	p := make([]string, len(projects))
+	for i, pr := range projects {
+		p[i] = pr.Path
+	}
+	return strings.Join(p, " ")
+}
+
+func encoded(t *testing.T, projects []*project.Project) string {
+	t.Helper()
+	data, err := json.Marshal(projects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	tempHome(t)
+	st := openSQLiteStore(t)
+
+	want := sortByPath(storeFixture())
+	if err := st.Save(storeFixture()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := st.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded(t, got) != encoded(t, want) {
+		t.Errorf("Load after Save:\n%s\nwant:\n%s", encoded(t, got), encoded(t, want))
+	}
+
+	// Upsert replaces by path and adds new paths.
+	changed := *want[0]
+	changed.Note = "updated"
+	added := &project.Project{Name: "new", Path: "/dev/new", Status: "active"}
+	if err := st.Upsert(&changed, added); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := st.Get(changed.Path); err != nil || p == nil || p.Note != "updated" {
+		t.Errorf("Get(%s) after Upsert = %+v, %v", changed.Path, p, err)
+	}
+	if p, err := st.Get("id-web"); err != nil || p == nil || p.Path != "/dev/work/web" {
+		t.Errorf("Get by ID = %+v, %v", p, err)
+	}
+	if p, err := st.Get("/dev/none"); err != nil || p != nil {
+		t.Errorf("Get(unknown) = %+v, %v; want nil", p, err)
+	}
+
+	if err := st.Delete("/dev/new", "/dev/old"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = st.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/dev/forks/lib /dev/goal /dev/work/api /dev/work/web"; paths(got) != want {
+		t.Errorf("after Delete: %s, want %s", paths(got), want)
+	}
+
+	// Save replaces everything.
+	if err := st.Save(storeFixture()[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := st.Load(); paths(got) != "/dev/work/api" {
+		t.Errorf("after a second Save: %s", paths(got))
+	}
+}
+
+func TestQueryBackendsAgree(t *testing.T) {
+	tempHome(t)
+	jst := &JSONStore{path: Path()}
+	sst := openSQLiteStore(t)
+	for _, st := range []Store{jst, sst} {
+		if err := st.Save(storeFixture()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	where := func(src string) *expr.Expr {
+		e, err := expr.Compile(src, project.Fields)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", src, err)
+		}
+		return e
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{"everything", Query{}, "/dev/forks/lib /dev/goal /dev/old /dev/work/api /dev/work/web"},
+		{"status", Query{Status: "active"}, "/dev/work/api"},
+		{"status from a mark", Query{Status: "archived"}, "/dev/old"},
+		{"mark", Query{Mark: "focus"}, "/dev/forks/lib"},
+		{"type substring, any case", Query{Type: "GO"}, "/dev/old /dev/work/api"},
+		{"tech substring", Query{Tech: "reac"}, "/dev/work/web"},
+		{"tech inside another name", Query{Tech: "go"}, "/dev/goal /dev/old /dev/work/api"},
+		{"tag, any case", Query{Tag: "acme"}, "/dev/work/api"},
+		{"own", Query{Own: true}, "/dev/goal /dev/old /dev/work/api /dev/work/web"},
+		{"forks", Query{Forks: true}, "/dev/forks/lib"},
+		{"search note", Query{Search: "may"}, "/dev/work/web"},
+		{"search tag", Query{Search: "acm"}, "/dev/work/api"},
+		{"combined", Query{Type: "go", Own: true, Status: "active"}, "/dev/work/api"},
+		{"where", Query{Where: where("commits > 10 and tech in (go, react)")}, "/dev/work/api /dev/work/web"},
+		{"where with flags", Query{Forks: true, Where: where("commits > 10")}, ""},
+		{"no match", Query{Status: "nope"}, ""},
+	}
+	for _, tt := range tests {
+		for _, st := range []Store{jst, sst} {
+			got, err := st.Query(tt.q)
+			if err != nil {
+				t.Errorf("%s: %T.Query: %v", tt.name, st, err)
+				continue
+			}
+			if paths(got) != tt.want {
+				t.Errorf("%s: %T.Query = %q, want %q", tt.name, st, paths(got), tt.want)
+			}
+		}
+	}
+}
+
+func TestCopyBetweenBackends(t *testing.T) {
+	tempHome(t)
+	jst := &JSONStore{path: Path()}
+	if err := jst.Save(storeFixture()); err != nil {
+		t.Fatal(err)
+	}
+	want := encoded(t, sortByPath(storeFixture()))
+
+	sst := openSQLiteStore(t)
+	if n, err := Copy(jst, sst); err != nil || n != len(storeFixture()) {
+		t.Fatalf("Copy to SQLite = %d, %v", n, err)
+	}
+	if got, err := sst.Load(); err != nil || encoded(t, got) != want {
+		t.Errorf("SQLite after Copy = %s, %v", encoded(t, got), err)
+	}
+
+	// Back to an emptied JSON store.
+	if err := jst.Save(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := Copy(sst, jst); err != nil || n != len(storeFixture()) {
+		t.Fatalf("Copy to JSON = %d, %v", n, err)
+	}
+	if got, err := jst.Load(); err != nil || encoded(t, got) != want {
+		t.Errorf("JSON after Copy = %s, %v", encoded(t, got), err)
+	}
+}
@@ -1,58 +1,179 @@
 package store
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
+	"strings"
 
-	"github.com/peeomid/prj/internal/config"
+	"github.com/peeomid/prj/internal/expr"
 	"github.com/peeomid/prj/internal/project"
 )
 
-func Path() string {
-	return filepath.Join(config.Dir(), "projects.json")
+// Backends selectable with the "storage" config setting.
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// Store persists scanned projects. Projects are keyed by Path.
+type Store interface {
+	// Load returns every stored project, sorted by path.
+	Load() ([]*project.Project, error)
+	// Get returns the project stored at path, or with that stable ID, or
+	// nil if there is none.
+	Get(key string) (*project.Project, error)
+	// Upsert inserts or replaces projects by path.
+	Upsert(projects ...*project.Project) error
+	// Delete removes the projects stored at the given paths.
+	Delete(paths ...string) error
+	// Query returns the stored projects matching q, sorted by path.
+	Query(q Query) ([]*project.Project, error)
+	// Save replaces the whole store with projects.
+	Save(projects []*project.Project) error
+	// Location is the file backing the store, for messages.
+	Location() string
+	Close() error
+}
+
+// Open returns the store for the given backend name ("" means JSON).
+func Open(backend string) (Store, error) {
+	switch backend {
+	case "", BackendJSON:
+		return &JSONStore{path: Path()}, nil
+	case BackendSQLite:
+		return OpenSQLite(SQLitePath())
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want %s or %s)", backend, BackendJSON, BackendSQLite)
+	}
 }
 
-// Load reads the projects from disk.
-func Load() ([]*project.Project, error) {
-	data, err := os.ReadFile(Path())
+// Copy replaces everything in dst with the projects in src and returns how
+// many were copied. "prj store convert" uses it to switch backends.
+func Copy(src, dst Store) (int, error) {
+	projects, err := src.Load()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, err
+		return 0, fmt.Errorf("load projects: %w", err)
 	}
-	var projects []*project.Project
-	if err := json.Unmarshal(data, &projects); err != nil {
-		return nil, err
+	if err := dst.Save(projects); err != nil {
+		return 0, fmt.Errorf("save projects: %w", err)
 	}
-	return projects, nil
+	return len(projects), nil
+}
+
+// Query selects projects, mirroring the "prj list" filters. Zero fields
+// match everything.
+type Query struct {
+	Status string // exact effective status (marks like "archived" included)
+	Mark   string // exact mark
+	Type   string // substring of the inferred type
+	Tech   string // substring of any tech stack entry
+	Tag    string // exact tag (case-insensitive)
+	Own    bool   // exclude forks
+	Forks  bool   // only forks
+	Search string // substring of name, path, a tag or the note
+	// Where is a compiled expression over project.Fields ("prj list --where").
+	Where *expr.Expr
 }
 
-// Save writes projects to disk.
-func Save(projects []*project.Project) error {
-	if err := os.MkdirAll(config.Dir(), 0755); err != nil {
-		return err
+// Match reports whether p satisfies q. Backends may pre-filter however they
+// like but must agree with Match.
+func (q Query) Match(p *project.Project) bool {
+	if q.Status != "" && p.EffectiveStatus() != q.Status {
+		return false
 	}
-	data, err := json.MarshalIndent(projects, "", "  ")
-	if err != nil {
-		return err
+	if q.Mark != "" && p.Mark != q.Mark {
+		return false
+	}
+	if q.Type != "" && !strings.Contains(strings.ToLower(p.InferredType), strings.ToLower(q.Type)) {
+		return false
+	}
+	if q.Tech != "" && !containsTech(p.TechStack, q.Tech) {
+		return false
+	}
+	if q.Tag != "" && !p.HasTag(q.Tag) {
+		return false
+	}
+	if q.Own && p.IsFork {
+		return false
+	}
+	if q.Forks && !p.IsFork {
+		return false
+	}
+	if q.Search != "" {
+		s := strings.ToLower(q.Search)
+		if !strings.Contains(strings.ToLower(p.Name), s) &&
+			!strings.Contains(strings.ToLower(p.Path), s) &&
+			!strings.Contains(strings.ToLower(p.Note), s) &&
+			!containsTag(p.Tags, s) {
+			return false
+		}
 	}
-	return os.WriteFile(Path(), data, 0644)
+	if q.Where != nil && !q.Where.Eval(p) {
+		return false
+	}
+	return true
+}
+
+func containsTech(stack []string, tech string) bool {
+	t := strings.ToLower(tech)
+	for _, s := range stack {
+		if strings.Contains(strings.ToLower(s), t) {
+			return true
+		}
+	}
+	return false
 }
 
-// Merge upserts scanned projects into existing ones (by path).
-func Merge(existing, scanned []*project.Project) []*project.Project {
-	byPath := make(map[string]*project.Project)
-	for _, p := range existing {
-		byPath[p.Path] = p
+func containsTag(tags []string, s string) bool {
+	for _, t := range tags {
+		if strings.Contains(strings.ToLower(t), s) {
+			return true
+		}
 	}
+	return false
+}
+
+// Merge upserts scanned projects into existing ones. A scanned project
+// replaces the stored one at the same path; failing that, a stored project
+// with the same stable ID whose path was not part of this scan is treated as
+// the same repo having moved, and is updated in place with the move
+// recorded. Everything else is added. Returns the merged list and the
+// projects that moved.
+func Merge(existing, scanned []*project.Project) (merged, moved []*project.Project) {
+	scannedPaths := make(map[string]bool, len(scanned))
 	for _, p := range scanned {
-		byPath[p.Path] = p
+		scannedPaths[p.Path] = true
 	}
-	result := make([]*project.Project, 0, len(byPath))
-	for _, p := range byPath {
-		result = append(result, p)
+
+	byPath := make(map[string]int, len(existing))
+	byID := make(map[string]int, len(existing))
+	merged = make([]*project.Project, len(existing))
+	for i, p := range existing {
+		merged[i] = p
+		byPath[p.Path] = i
+		if p.ID != "" && !scannedPaths[p.Path] {
+			byID[p.ID] = i
+		}
+	}
+
+	for _, p := range scanned {
+		if i, ok := byPath[p.Path]; ok {
+			p.Inherit(merged[i])
+			merged[i] = p
+			continue
+		}
+		if i, ok := byID[p.ID]; ok && p.ID != "" {
+			old := merged[i]
+			delete(byID, p.ID)
+			delete(byPath, old.Path)
+			p.Inherit(old)
+			p.Moves = append(p.Moves, project.PathMove{From: old.Path, To: p.Path, At: p.ScannedAt})
+			merged[i] = p
+			byPath[p.Path] = i
+			moved = append(moved, p)
+			continue
+		}
+		byPath[p.Path] = len(merged)
+		merged = append(merged, p)
 	}
-	return result
+	return merged, moved
 }
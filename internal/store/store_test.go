@@ -0,0 +1,86 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/peeomid/prj/internal/project"
+)
+
+func TestMergeMovedRepo(t *testing.T) {
+	existing := []*project.Project{
+		{ID: "id-api", Name: "api", Path: "/dev/api", Mark: "focus", Tags: []string{"acme"}, Note: "ship it"},
+		{ID: "id-web", Name: "web", Path: "/dev/web"},
+	}
+	scanned := []*project.Project{
+		{ID: "id-api", Name: "api", Path: "/archive/api", ScannedAt: "2026-03-01T00:00:00Z"},
+		{ID: "id-web", Name: "web", Path: "/dev/web"},
+	}
+
+	merged, moved := Merge(existing, scanned)
+	if len(merged) != 2 {
+		t.Fatalf("merged %d projects, want 2 (no duplicate for the move)", len(merged))
+	}
+	if len(moved) != 1 || moved[0].ID != "id-api" {
+		t.Fatalf("moved = %v, want the api project", moved)
+	}
+	p := merged[0]
+	if p.Path != "/archive/api" || p.ID != "id-api" {
+		t.Errorf("moved project kept its slot as %s (%s), want /archive/api (id-api)", p.Path, p.ID)
+	}
+	if p.Mark != "focus" || p.Note != "ship it" || len(p.Tags) != 1 {
+		t.Errorf("moved project lost its annotations: %+v", p)
+	}
+	want := project.PathMove{From: "/dev/api", To: "/archive/api", At: "2026-03-01T00:00:00Z"}
+	if len(p.Moves) != 1 || p.Moves[0] != want {
+		t.Errorf("Moves = %v, want [%v]", p.Moves, want)
+	}
+
+	// Moving again appends to the history.
+	again := []*project.Project{{ID: "id-api", Name: "api", Path: "/old/api", ScannedAt: "2026-04-01T00:00:00Z"}}
+	merged, moved = Merge(merged, again)
+	if len(merged) != 2 || len(moved) != 1 {
+		t.Fatalf("second move: %d merged, %d moved", len(merged), len(moved))
+	}
+	if m := merged[0].Moves; len(m) != 2 || m[0].To != "/archive/api" || m[1].From != "/archive/api" || m[1].To != "/old/api" {
+		t.Errorf("Moves after second move = %v", m)
+	}
+}
+
+func TestMergeSamePath(t *testing.T) {
+	existing := []*project.Project{{ID: "id-api", Name: "api", Path: "/dev/api", Note: "keep"}}
+	scanned := []*project.Project{{ID: "id-api", Name: "api", Path: "/dev/api", Status: "active"}}
+	merged, moved := Merge(existing, scanned)
+	if len(merged) != 1 || len(moved) != 0 {
+		t.Fatalf("%d merged, %d moved; want 1, 0", len(merged), len(moved))
+	}
+	if p := merged[0]; p.Status != "active" || p.Note != "keep" || len(p.Moves) != 0 {
+		t.Errorf("merged = %+v", p)
+	}
+}
+
+func TestMergeClone(t *testing.T) {
+	// A second checkout of the same repo shares its ID, but the original
+	// was scanned too, so it's a new project rather than a move.
+	existing := []*project.Project{{ID: "id-api", Name: "api", Path: "/dev/api", Note: "original"}}
+	scanned := []*project.Project{
+		{ID: "id-api", Name: "api", Path: "/dev/api"},
+		{ID: "id-api", Name: "api", Path: "/tmp/api"},
+	}
+	merged, moved := Merge(existing, scanned)
+	if len(merged) != 2 || len(moved) != 0 {
+		t.Fatalf("%d merged, %d moved; want 2, 0", len(merged), len(moved))
+	}
+	if merged[0].Note != "original" || merged[1].Note != "" {
+		t.Errorf("notes = %q, %q; the clone shouldn't inherit", merged[0].Note, merged[1].Note)
+	}
+}
+
+func TestMergeWithoutID(t *testing.T) {
+	// Repos without commits have no ID and can't be tracked across moves.
+	existing := []*project.Project{{Name: "new", Path: "/dev/new", Note: "x"}}
+	scanned := []*project.Project{{Name: "new", Path: "/archive/new"}}
+	merged, moved := Merge(existing, scanned)
+	if len(merged) != 2 || len(moved) != 0 {
+		t.Errorf("%d merged, %d moved; want 2, 0", len(merged), len(moved))
+	}
+}
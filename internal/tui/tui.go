@@ -0,0 +1,397 @@
+// Package tui is the full-screen project browser behind "prj ui".
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/ansi"
+	"github.com/muesli/reflow/truncate"
+	"github.com/peeomid/prj/internal/display"
+	"github.com/peeomid/prj/internal/project"
+)
+
+// Options wires the browser to the rest of prj.
+type Options struct {
+	Projects []*project.Project
+	// Sort is the initial order, one of project.SortKeys.
+	Sort string
+	// Query is the initial search.
+	Query string
+	// Rescan re-extracts one project, saves it and returns the new version.
+	Rescan func(p *project.Project) (*project.Project, error)
+	// Editor returns the command that opens a directory in the user's
+	// editor.
+	Editor func(dir string) *exec.Cmd
+	// Copy puts text on the clipboard.
+	Copy func(text string) error
+}
+
+// Run shows the browser until the user quits.
+func Run(opts Options) error {
+	m := &model{opts: opts, all: opts.Projects, sort: opts.Sort, query: opts.Query}
+	if m.sort == "" {
+		m.sort = project.SortKeys[0]
+	}
+	m.refilter()
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+// splitWidth is the terminal width from which the detail pane is shown
+// next to the list instead of on its own (enter).
+const splitWidth = 90
+
+type model struct {
+	opts Options
+
+	all     []*project.Project
+	visible []*project.Project
+	cursor  int
+	offset  int // first visible list row
+
+	query  string
+	typing bool
+	sort   string
+	detail bool // detail pane full screen
+	scroll int  // detail pane scroll
+	status string
+	busy   bool
+	width  int
+	height int
+}
+
+type rescannedMsg struct {
+	old, p *project.Project
+	err    error
+}
+
+type editorDoneMsg struct{ err error }
+
+func (m *model) Init() tea.Cmd { return nil }
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampOffset()
+		return m, nil
+
+	case rescannedMsg:
+		m.busy = false
+		if msg.err != nil {
+			m.status = display.Red("rescan failed: ") + msg.err.Error()
+			return m, nil
+		}
+		for i, p := range m.all {
+			if p == msg.old {
+				m.all[i] = msg.p
+			}
+		}
+		m.refilter()
+		m.status = display.Green("rescanned ") + msg.p.Name
+		return m, nil
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.status = display.Red("editor: ") + msg.err.Error()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.typing {
+			return m, m.typeKey(msg)
+		}
+		return m, m.key(msg)
+	}
+	return m, nil
+}
+
+// typeKey edits the search query.
+func (m *model) typeKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		m.typing = false
+		m.query = ""
+	case tea.KeyEnter:
+		m.typing = false
+	case tea.KeyBackspace:
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case tea.KeyUp, tea.KeyDown:
+		m.move(map[tea.KeyType]int{tea.KeyUp: -1, tea.KeyDown: 1}[msg.Type])
+		return nil
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	default:
+		return nil
+	}
+	m.refilter()
+	return nil
+}
+
+func (m *model) key(msg tea.KeyMsg) tea.Cmd {
+	m.status = ""
+	if msg.Type == tea.KeyRunes && len(msg.Runes) > 1 && msg.Runes[0] == '/' {
+		// "/" and what follows arrived in one read (fast typing, paste).
+		m.typing, m.detail = true, false
+		m.query += string(msg.Runes[1:])
+		m.refilter()
+		return nil
+	}
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc":
+		switch {
+		case m.detail:
+			m.detail = false
+		case m.query != "":
+			m.query = ""
+			m.refilter()
+		default:
+			return tea.Quit
+		}
+	case "/":
+		m.typing = true
+		m.detail = false
+	case "up", "k":
+		if m.detail {
+			m.scroll = max(m.scroll-1, 0)
+		} else {
+			m.move(-1)
+		}
+	case "down", "j":
+		if m.detail {
+			m.scroll++
+		} else {
+			m.move(1)
+		}
+	case "pgup", "ctrl+u":
+		m.move(-m.listHeight())
+	case "pgdown", "ctrl+d":
+		m.move(m.listHeight())
+	case "home", "g":
+		m.move(-len(m.visible))
+	case "end", "G":
+		m.move(len(m.visible))
+	case "enter", "tab":
+		m.detail = !m.detail
+		m.scroll = 0
+	case "s":
+		for i, k := range project.SortKeys {
+			if k == m.sort {
+				m.sort = project.SortKeys[(i+1)%len(project.SortKeys)]
+				break
+			}
+		}
+		m.refilter()
+		m.status = "sorted by " + m.sort
+	case "e":
+		if p := m.selected(); p != nil && m.opts.Editor != nil {
+			return tea.ExecProcess(m.opts.Editor(p.Path), func(err error) tea.Msg { return editorDoneMsg{err} })
+		}
+	case "c", "y":
+		if p := m.selected(); p != nil && m.opts.Copy != nil {
+			if err := m.opts.Copy(p.Path); err != nil {
+				m.status = display.Red("copy: ") + err.Error()
+			} else {
+				m.status = "copied " + p.Path
+			}
+		}
+	case "r":
+		p := m.selected()
+		if p == nil || m.opts.Rescan == nil || m.busy {
+			return nil
+		}
+		m.busy = true
+		m.status = "rescanning " + p.Name + "…"
+		return func() tea.Msg {
+			fresh, err := m.opts.Rescan(p)
+			return rescannedMsg{old: p, p: fresh, err: err}
+		}
+	}
+	return nil
+}
+
+// refilter recomputes the visible list: ranked matches while searching,
+// everything in the chosen order otherwise. The cursor stays on the same
+// project when it is still visible.
+func (m *model) refilter() {
+	prev := m.selected()
+	if m.query != "" {
+		ranked := project.Rank(m.all, m.query)
+		m.visible = make([]*project.Project, len(ranked))
+		for i, c := range ranked {
+			m.visible[i] = c.Project
+		}
+	} else {
+		m.visible = append([]*project.Project(nil), m.all...)
+		project.SortBy(m.visible, m.sort)
+	}
+
+	m.cursor = 0
+	for i, p := range m.visible {
+		if prev != nil && p.Path == prev.Path {
+			m.cursor = i
+		}
+	}
+	m.clampOffset()
+}
+
+func (m *model) selected() *project.Project {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return m.visible[m.cursor]
+}
+
+func (m *model) move(n int) {
+	m.cursor = min(max(m.cursor+n, 0), max(len(m.visible)-1, 0))
+	m.scroll = 0
+	m.clampOffset()
+}
+
+// clampOffset scrolls the list so the cursor is on screen.
+func (m *model) clampOffset() {
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// listHeight is the number of list rows: the screen minus the header and
+// footer lines.
+func (m *model) listHeight() int {
+	return max(m.height-3, 1)
+}
+
+func (m *model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(m.header() + "\n")
+
+	body := m.listLines(m.width)
+	switch {
+	case m.detail:
+		body = m.detailLines(m.width)
+	case m.width >= splitWidth:
+		listW := min(m.width*2/5, 56)
+		list := m.listLines(listW)
+		detail := m.detailLines(m.width - listW - 3)
+		body = make([]string, m.listHeight())
+		for i := range body {
+			l, d := "", ""
+			if i < len(list) {
+				l = list[i]
+			}
+			if i < len(detail) {
+				d = detail[i]
+			}
+			body[i] = pad(l, listW) + display.Gray(" │ ") + d
+		}
+	}
+	for i := 0; i < m.listHeight(); i++ {
+		if i < len(body) {
+			b.WriteString(body[i])
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(m.footer())
+	return b.String()
+}
+
+func (m *model) header() string {
+	h := fmt.Sprintf(" %s  %d/%d projects  ·  sort: %s", display.Bold("prj"), len(m.visible), len(m.all), m.sort)
+	switch {
+	case m.typing:
+		h += "  ·  search: " + m.query + "█"
+	case m.query != "":
+		h += "  ·  search: " + display.Cyan(m.query)
+	}
+	return fit(h, m.width)
+}
+
+func (m *model) footer() string {
+	if m.status != "" {
+		return fit(" "+m.status, m.width)
+	}
+	help := "↑↓ move  / search  s sort  enter detail  e editor  c copy path  r rescan  q quit"
+	if m.typing {
+		help = "type to search  enter done  esc clear"
+	}
+	return fit(" "+display.Gray(help), m.width)
+}
+
+func (m *model) listLines(width int) []string {
+	h := m.listHeight()
+	if len(m.visible) == 0 {
+		return []string{display.Gray("  no matching projects")}
+	}
+	var lines []string
+	for i := m.offset; i < len(m.visible) && i < m.offset+h; i++ {
+		p := m.visible[i]
+		marker, name := "  ", p.Name
+		if i == m.cursor {
+			marker, name = display.Cyan("▸ "), display.Bold(p.Name)
+		}
+		status := display.StatusLabel(p)
+		if p.Missing != "" {
+			status = display.Red("missing")
+		}
+		nameW := max(width-24, 10)
+		line := marker + pad(fit(name, nameW), nameW) + " " + pad(status, 12) + " " + display.Gray(display.FormatAge(p.LastCommitDate))
+		lines = append(lines, fit(line, width))
+	}
+	return lines
+}
+
+func (m *model) detailLines(width int) []string {
+	p := m.selected()
+	if p == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	display.WriteDetail(&buf, p)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	m.scroll = min(m.scroll, max(len(lines)-m.listHeight(), 0))
+	lines = lines[m.scroll:]
+	for i, l := range lines {
+		lines[i] = fit(l, width)
+	}
+	return lines
+}
+
+// fit cuts s (which may hold color codes) to width cells.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if ansi.PrintableRuneWidth(s) <= width {
+		return s
+	}
+	return truncate.StringWithTail(s, uint(width), "…")
+}
+
+// pad right-fills s with spaces to width cells.
+func pad(s string, width int) string {
+	if n := width - ansi.PrintableRuneWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}